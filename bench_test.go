@@ -0,0 +1,118 @@
+package telegrammarkdown_test
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func typicalMessage() string {
+	return md.CombineWithNewLine(
+		md.Bold(md.Text("Deploy finished: "), md.ItalicText("api-server v1.2.3")),
+		md.CombineWithSpace(md.Text("by"), md.MentionUser("john_doe"), md.Text("in"), md.InlineFixWidth("prod-eu")),
+		md.InlineURL("Open dashboard", "https://example.com/d/(x)"),
+		md.Hashtag("deploy"),
+	).String()
+}
+
+func largeMessage() string {
+	message := md.Text("items:")
+	for i := 0; i < 100; i++ {
+		item := md.Bold(md.Text("item "+strconv.Itoa(i)+": "), md.Text("value-with.special_chars!"))
+		message = md.CombineWithNewLine(message, item)
+	}
+	return message.String()
+}
+
+func largeTable() *md.Table {
+	table := &md.Table{Separator: "|"}
+	table.AddColumns(
+		md.Column{Width: 12, Align: md.Left},
+		md.Column{Width: 8},
+		md.Column{Width: 8},
+	)
+	table.SetHeader("name", "cpu", "memory")
+	for i := 0; i < 150; i++ {
+		table.AddRow("service-"+strconv.Itoa(i), strconv.Itoa(i%100)+"%", strconv.Itoa(i*10)+"MB")
+	}
+	return table
+}
+
+func TestLargeMessageSize(t *testing.T) {
+	if n := len(largeMessage()); n < 4096 {
+		t.Errorf("large message is only %d bytes", n)
+	}
+	if n := len(largeTable().String()); n < 4096 {
+		t.Errorf("large table is only %d bytes", n)
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	plain := strings.Repeat("no special characters here ", 200)
+	table := largeTable()
+	tests := []struct {
+		name string
+		max  float64
+		fn   func()
+	}{
+		{"Text without escapes", 0, func() { md.Text(plain) }},
+		{"Text with escapes", 1, func() { md.Text("a.b-c") }},
+		{"Combine", 1, func() { md.Combine(md.Space(), md.Space(), md.Space()) }},
+		{"typical message", 40, func() { typicalMessage() }},
+		{"large message", 600, func() { largeMessage() }},
+		{"large table", 4, func() { _ = table.String() }},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(10, tt.fn); got > tt.max {
+			t.Errorf("%s: %v allocations, want at most %v", tt.name, got, tt.max)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	var out strings.Builder
+	if _, err := largeTable().WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	want := largeTable().String()
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func BenchmarkTypicalMessage(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		typicalMessage()
+	}
+}
+
+func BenchmarkLargeMessage(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		largeMessage()
+	}
+}
+
+func BenchmarkLargeTable(b *testing.B) {
+	table := largeTable()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = table.String()
+	}
+}
+
+func BenchmarkLargeTableWriteTo(b *testing.B) {
+	table := largeTable()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.WriteTo(ioutil.Discard)
+	}
+}
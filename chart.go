@@ -0,0 +1,225 @@
+package telegrammarkdown
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultChartWidth  = 30
+	defaultChartHeight = 8
+)
+
+type ChartPoint struct {
+	Label string
+	Value float64
+}
+
+type Chart struct {
+	Width  int
+	Height int
+	Format func(v float64) string
+}
+
+func BarChart(points []ChartPoint, width int) styledText {
+	return Chart{Width: width}.Bars(points)
+}
+
+func ColumnChart(points []ChartPoint, height int) styledText {
+	return Chart{Height: height}.Columns(points)
+}
+
+func LineChart(values []float64, width, height int) styledText {
+	return Chart{Width: width, Height: height}.Line(values)
+}
+
+func (c Chart) width() int {
+	if c.Width <= 0 {
+		return defaultChartWidth
+	}
+	return c.Width
+}
+
+func (c Chart) height() int {
+	if c.Height <= 0 {
+		return defaultChartHeight
+	}
+	return c.Height
+}
+
+func (c Chart) format(v float64) string {
+	if c.Format != nil {
+		return c.Format(v)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (c Chart) Bars(points []ChartPoint) styledText {
+	labelWidth, valueWidth, max := 0, 0, 0.0
+	values := make([]string, len(points))
+	for i, p := range points {
+		values[i] = c.format(p.Value)
+		labelWidth = maxInt(labelWidth, utf8.RuneCountInString(p.Label))
+		valueWidth = maxInt(valueWidth, utf8.RuneCountInString(values[i]))
+		max = finiteMax(max, p.Value)
+	}
+	barWidth := maxInt(1, c.width()-labelWidth-valueWidth-2)
+
+	lines := make([]string, len(points))
+	for i, p := range points {
+		lines[i] = alignText(p.Label, labelWidth, Left) + " " +
+			ProgressBar(p.Value, max, barWidth).text + " " +
+			alignText(values[i], valueWidth, Right)
+	}
+	return chartBlock(lines)
+}
+
+func (c Chart) Columns(points []ChartPoint) styledText {
+	height := c.height()
+	columnWidth, max := 1, 0.0
+	for _, p := range points {
+		columnWidth = maxInt(columnWidth, utf8.RuneCountInString(p.Label))
+		max = finiteMax(max, p.Value)
+	}
+	top, bottom := c.format(max), c.format(0)
+	axisWidth := maxInt(utf8.RuneCountInString(top), utf8.RuneCountInString(bottom))
+
+	var lines []string
+	for row := height - 1; row >= 0; row-- {
+		line := axisLabel(row, height, top, bottom, axisWidth)
+		for _, p := range points {
+			eighths := int(math.Round(ratio(p.Value, max)*float64(height*8))) - row*8
+			cell := " "
+			if eighths > 0 {
+				cell = string(sparkBlocks[minInt(eighths, 8)-1])
+			}
+			line += " " + strings.Repeat(cell, columnWidth)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, strings.Repeat(" ", axisWidth+1)+"└"+
+		strings.Repeat("─", len(points)*(columnWidth+1)))
+
+	labels := strings.Repeat(" ", axisWidth+2)
+	for _, p := range points {
+		labels += " " + alignText(p.Label, columnWidth, Center)
+	}
+	return chartBlock(append(lines, labels))
+}
+
+func (c Chart) Line(values []float64) styledText {
+	width, height := c.width(), c.height()
+	values = resample(values, width)
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+	}
+	if math.IsInf(min, 0) {
+		min, max = 0, 0
+	}
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", len(values)))
+	}
+	previous := -1
+	for i, v := range values {
+		if math.IsNaN(v) {
+			previous = -1
+			continue
+		}
+		row := height / 2
+		switch {
+		case math.IsInf(v, 1):
+			row = height - 1
+		case math.IsInf(v, -1):
+			row = 0
+		case max > min:
+			row = int(math.Round(position(v, min, max) * float64(height-1)))
+		}
+		if previous >= 0 {
+			for r := minInt(previous, row) + 1; r < maxInt(previous, row); r++ {
+				grid[height-1-r][i] = '│'
+			}
+		}
+		grid[height-1-row][i] = '•'
+		previous = row
+	}
+
+	top, bottom := c.format(max), c.format(min)
+	axisWidth := maxInt(utf8.RuneCountInString(top), utf8.RuneCountInString(bottom))
+	lines := make([]string, 0, height+1)
+	for i, cells := range grid {
+		lines = append(lines, axisLabel(height-1-i, height, top, bottom, axisWidth)+string(cells))
+	}
+	lines = append(lines, strings.Repeat(" ", axisWidth+1)+"└"+strings.Repeat("─", len(values)))
+	return chartBlock(lines)
+}
+
+func axisLabel(row, height int, top, bottom string, width int) string {
+	switch row {
+	case height - 1:
+		return alignText(top, width, Right) + " ┤"
+	case 0:
+		return alignText(bottom, width, Right) + " ┤"
+	}
+	return strings.Repeat(" ", width) + " │"
+}
+
+func resample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	sampled := make([]float64, width)
+	for i := range sampled {
+		from, to := i*len(values)/width, (i+1)*len(values)/width
+		var sum float64
+		count := 0
+		for _, v := range values[from:to] {
+			if !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		sampled[i] = math.NaN()
+		if count > 0 {
+			sampled[i] = sum / float64(count)
+		}
+	}
+	return sampled
+}
+
+func chartBlock(lines []string) styledText {
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return CodeBlock("", strings.Join(lines, "\n")+"\n")
+}
+
+// finiteMax ignores NaN and infinite values, which would otherwise become
+// the scale of the whole chart.
+func finiteMax(max, v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return max
+	}
+	return math.Max(max, v)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
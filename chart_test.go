@@ -0,0 +1,97 @@
+package telegrammarkdown_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestBarChart(t *testing.T) {
+	got := md.BarChart([]md.ChartPoint{
+		{Label: "api", Value: 4},
+		{Label: "db", Value: 2},
+	}, 10)
+	want := "```\n"
+	want += "api ████ 4\n"
+	want += "db  ██   2\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestColumnChart(t *testing.T) {
+	got := md.ColumnChart([]md.ChartPoint{
+		{Label: "a", Value: 4},
+		{Label: "b", Value: 1},
+		{Label: "c", Value: 2},
+	}, 2)
+	want := "```\n"
+	want += "4 ┤ █\n"
+	want += "0 ┤ █ ▄ █\n"
+	want += "  └──────\n"
+	want += "    a b c\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestLineChart(t *testing.T) {
+	got := md.LineChart([]float64{0, 3, 1, 2}, 10, 4)
+	want := "```\n"
+	want += "3 ┤ •\n"
+	want += "  │ ││•\n"
+	want += "  │ │•\n"
+	want += "0 ┤•\n"
+	want += "  └────\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestChartEscapesLabels(t *testing.T) {
+	got := md.BarChart([]md.ChartPoint{{Label: "`x`", Value: 1}}, 7)
+	want := "```\n"
+	want += "\\`x\\` █ 1\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestChartsWithNonFiniteValues(t *testing.T) {
+	points := []md.ChartPoint{{"a", math.Inf(1)}, {"b", 2}, {"c", math.NaN()}, {"d", 4}}
+
+	got := md.BarChart(points, 12).String()
+	want := "```\na █████ +Inf\nb ██▌      2\nc        NaN\nd █████    4\n```\n"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+
+	got = md.ColumnChart(points, 2).String()
+	want = "```\n4 ┤ █     █\n0 ┤ █ █   █\n  └────────\n    a b c d\n```\n"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+
+	got = md.LineChart([]float64{0, math.Inf(1), 2, math.Inf(-1), math.NaN(), 4}, 10, 4).String()
+	want = "```\n4 ┤ •   •\n  │ │•\n  │ │ │\n0 ┤•  •\n  └──────\n```\n"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+
+	chart := md.Chart{Height: 4, Format: func(v float64) string { return strconv.FormatFloat(v, 'g', 2, 64) }}
+	got = chart.Line([]float64{-math.MaxFloat64, math.MaxFloat64, 0}).String()
+	want = "```\n 1.8e+308 ┤ •\n          │ │•\n          │ │\n-1.8e+308 ┤•\n          └───\n```\n"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
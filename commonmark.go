@@ -0,0 +1,828 @@
+package telegrammarkdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	asciiPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	taskUnchecked    = "☐"
+	taskChecked      = "☑"
+)
+
+var (
+	atxHeading      = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextUnderline = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	thematicBreak   = regexp.MustCompile(`^ {0,3}((?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	codeFence       = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*?)[ \t]*$")
+	listMarker      = regexp.MustCompile(`^( {0,3})([-+*]|[0-9]{1,9}[.)])( +|$)`)
+	taskMarker      = regexp.MustCompile(`^\[([ xX])\][ \t]`)
+	tableDelimiter  = regexp.MustCompile(`^[ \t]*:?-+:?[ \t]*$`)
+	linkDefinition  = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?(?:[ \t]+(?:"[^"]*"|'[^']*'|\([^)]*\)))?[ \t]*$`)
+	htmlBlockStart  = regexp.MustCompile(`^ {0,3}<(?:!--|/?[A-Za-z][A-Za-z0-9-]*(?:[\s/>]|$))`)
+	htmlComment     = regexp.MustCompile(`^<!--[\s\S]*?-->`)
+	htmlTag         = regexp.MustCompile(`^</?([A-Za-z][A-Za-z0-9-]*)(?:\s[^<>]*)?/?>`)
+	uriAutolink     = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailAutolink   = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9.-]+)>`)
+	entity          = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	linkTitle       = regexp.MustCompile(`^[ \t\n]*(?:"[^"]*"|'[^']*'|\([^)]*\))?[ \t\n]*\)`)
+)
+
+type CommonMarkOptions struct {
+	HeadingStyles map[int]HeadingStyle
+	// HardLineBreaks keeps the line breaks inside paragraphs, as GitHub does
+	// for comments, instead of joining the lines with a space.
+	HardLineBreaks bool
+}
+
+func FromCommonMark(input string) styledText {
+	return CommonMarkOptions{}.Convert(input)
+}
+
+func (o CommonMarkOptions) Convert(input string) styledText {
+	c := &commonMark{options: o, references: map[string]string{}}
+	lines := strings.Split(normalizeLineEndings(input), "\n")
+	for i, line := range lines {
+		lines[i] = expandIndent(line)
+	}
+	doc := c.blocks(c.collectReferences(lines))
+	return doc.Styled()
+}
+
+type commonMark struct {
+	options    CommonMarkOptions
+	references map[string]string
+}
+
+// collectReferences removes the link reference definitions from the input,
+// leaving the content of code blocks untouched.
+func (c *commonMark) collectReferences(lines []string) []string {
+	kept := lines[:0]
+	fence := ""
+	for _, line := range lines {
+		if m := codeFence.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[2]
+			case m[3] == "" && m[2][0] == fence[0] && len(m[2]) >= len(fence):
+				fence = ""
+			}
+		}
+		if m := linkDefinition.FindStringSubmatch(line); m != nil && fence == "" {
+			label := referenceLabel(m[1])
+			if _, ok := c.references[label]; !ok {
+				c.references[label] = unescapeBackslashes(m[2])
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+func (c *commonMark) blocks(lines []string) *Document {
+	doc := &Document{HeadingStyles: c.options.HeadingStyles}
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			doc.Add(c.inline(strings.TrimRight(strings.Join(paragraph, "\n"), " ")))
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		indent := indentation(line)
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			i++
+		case indent >= 4 && len(paragraph) == 0:
+			end := i
+			for end < len(lines) && (indentation(lines[end]) >= 4 || strings.TrimSpace(lines[end]) == "") {
+				end++
+			}
+			for end > i && strings.TrimSpace(lines[end-1]) == "" {
+				end--
+			}
+			code := make([]string, 0, end-i)
+			for _, l := range lines[i:end] {
+				code = append(code, trimIndent(l, 4))
+			}
+			doc.Add(fencedCode("", code))
+			i = end
+		case codeFence.MatchString(line):
+			flush()
+			i = c.fenced(doc, lines, i)
+		case atxHeading.MatchString(line):
+			flush()
+			m := atxHeading.FindStringSubmatch(line)
+			doc.AddHeading(len(m[1]), c.inline(m[2]))
+			i++
+		case len(paragraph) > 0 && setextUnderline.MatchString(line):
+			level := 1
+			if strings.TrimSpace(line)[0] == '-' {
+				level = 2
+			}
+			doc.AddHeading(level, c.inline(strings.Join(paragraph, "\n")))
+			paragraph = nil
+			i++
+		case thematicBreak.MatchString(line):
+			flush()
+			doc.AddRule()
+			i++
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				rest := strings.TrimLeft(lines[i], " ")
+				if strings.HasPrefix(rest, ">") {
+					rest = strings.TrimPrefix(rest[1:], " ")
+				} else if strings.TrimSpace(lines[i]) == "" || isBlockStart(lines[i]) {
+					break
+				}
+				quoted = append(quoted, rest)
+			}
+			doc.Add(Blockquote(c.blocks(quoted).Styled()))
+		case listMarker.MatchString(line) && (len(paragraph) == 0 || canInterrupt(line)):
+			flush()
+			i = c.list(doc, lines, i)
+		case len(paragraph) == 0 && htmlBlockStart.MatchString(line):
+			end := i + 1
+			if strings.HasPrefix(strings.TrimSpace(line), "<!--") {
+				for end <= len(lines) && !strings.Contains(lines[end-1], "-->") {
+					end++
+				}
+				if end > len(lines) {
+					end = len(lines)
+				}
+			} else {
+				for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+					end++
+				}
+			}
+			block := c.inline(strings.Join(lines[i:end], "\n"))
+			if block.text = strings.TrimSpace(block.text); block.text != "" {
+				doc.Add(block)
+			}
+			i = end
+		case i+1 < len(lines) && strings.Contains(line, "|") && isDelimiterRow(lines[i+1]):
+			header, delimiters := splitTableRow(line), splitTableRow(lines[i+1])
+			if len(header) != len(delimiters) {
+				paragraph = append(paragraph, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			flush()
+			i = c.table(doc, lines, i)
+		default:
+			paragraph = append(paragraph, strings.TrimLeft(line, " "))
+			i++
+		}
+	}
+	flush()
+	return doc
+}
+
+func (c *commonMark) fenced(doc *Document, lines []string, start int) int {
+	m := codeFence.FindStringSubmatch(lines[start])
+	indent, fence := len(m[1]), m[2]
+	language := strings.Fields(m[3])
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if closing := codeFence.FindStringSubmatch(lines[i]); closing != nil && closing[3] == "" &&
+			closing[2][0] == fence[0] && len(closing[2]) >= len(fence) {
+			i++
+			break
+		}
+		code = append(code, trimIndent(lines[i], indent))
+	}
+	lang := ""
+	if len(language) > 0 {
+		lang = unescapeBackslashes(language[0])
+	}
+	doc.Add(fencedCode(lang, code))
+	return i
+}
+
+func (c *commonMark) list(doc *Document, lines []string, start int) int {
+	first := listMarker.FindStringSubmatch(lines[start])
+	list := List{}
+	if kind := first[2]; kind[0] >= '0' && kind[0] <= '9' {
+		list.Ordered = true
+		number, _ := strconv.Atoi(kind[:len(kind)-1])
+		list.Start = &number
+	}
+
+	i := start
+	for i < len(lines) {
+		m := listMarker.FindStringSubmatch(lines[i])
+		if m == nil || !sameListType(first[2], m[2]) {
+			break
+		}
+		offset := len(m[1]) + len(m[2]) + len(m[3])
+		if len(m[3]) > 4 {
+			offset = len(m[1]) + len(m[2]) + 1
+		} else if len(m[3]) == 0 {
+			offset++
+		}
+		marker := len(m[1]) + len(m[2])
+		item := []string{trimIndent(lines[i][marker:], offset-marker)}
+		i++
+		for ; i < len(lines); i++ {
+			line := lines[i]
+			blank := strings.TrimSpace(line) == ""
+			switch {
+			case blank:
+				item = append(item, "")
+				continue
+			case indentation(line) >= offset:
+				item = append(item, trimIndent(line, offset))
+				continue
+			case strings.TrimSpace(lines[i-1]) != "" && !isBlockStart(line):
+				item = append(item, strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+		for len(item) > 1 && item[len(item)-1] == "" {
+			item = item[:len(item)-1]
+		}
+		list.Add(c.listItem(item))
+
+		if i < len(lines) && strings.TrimSpace(lines[i-1]) == "" && !listMarker.MatchString(lines[i]) {
+			break
+		}
+	}
+	doc.Add(list.Styled())
+	return i
+}
+
+// listItem converts the content of a list item. The blocks of a tight item,
+// one without blank lines, are not separated by an empty line.
+func (c *commonMark) listItem(lines []string) styledText {
+	marker := ""
+	if m := taskMarker.FindStringSubmatch(lines[0]); m != nil {
+		marker = taskUnchecked
+		if m[1] != " " {
+			marker = taskChecked
+		}
+		lines[0] = lines[0][len(m[0]):]
+	}
+
+	doc := c.blocks(lines)
+	item := doc.Styled()
+	if !containsBlankLine(lines) {
+		blocks := make([]styledText, 0, len(doc.blocks))
+		for _, block := range doc.blocks {
+			blocks = append(blocks, block.text)
+		}
+		item = CombineWithNewLine(blocks...)
+	}
+	if marker != "" {
+		return Combine(Text(marker), Space(), item)
+	}
+	return item
+}
+
+func (c *commonMark) table(doc *Document, lines []string, start int) int {
+	header := splitTableRow(lines[start])
+	delimiters := splitTableRow(lines[start+1])
+	rows := [][]string{c.tableCells(header, len(delimiters))}
+	i := start + 2
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !isBlockStart(lines[i]); i++ {
+		rows = append(rows, c.tableCells(splitTableRow(lines[i]), len(delimiters)))
+	}
+
+	table := &Table{Separator: " | ", CodeBlock: true}
+	for col, delimiter := range delimiters {
+		delimiter = strings.TrimSpace(delimiter)
+		column := Column{Align: Left}
+		if strings.HasSuffix(delimiter, ":") && !strings.HasPrefix(delimiter, ":") {
+			column.Align = Right
+		}
+		for _, row := range rows {
+			if w := utf8.RuneCountInString(row[col]); w > column.Width {
+				column.Width = w
+			}
+		}
+		if strings.HasPrefix(delimiter, ":") && strings.HasSuffix(delimiter, ":") {
+			centerCells(rows, col, column.Width)
+		}
+		table.AddColumns(column)
+	}
+	table.SetHeader(rows[0]...)
+	for _, row := range rows[1:] {
+		table.AddRow(row...)
+	}
+	doc.AddTable(table)
+	return i
+}
+
+// centerCells pads the cells of a centred column on the left, so that the
+// left-aligned column shows them centred.
+func centerCells(rows [][]string, col, width int) {
+	for _, row := range rows {
+		padding := width - utf8.RuneCountInString(row[col])
+		row[col] = strings.Repeat(" ", padding/2) + row[col]
+	}
+}
+
+func (c *commonMark) tableCells(cells []string, columns int) []string {
+	row := make([]string, columns)
+	for i := 0; i < columns && i < len(cells); i++ {
+		row[i] = Plain(c.inline(strings.TrimSpace(cells[i])))
+	}
+	return row
+}
+
+func fencedCode(language string, lines []string) styledText {
+	code := strings.Join(lines, "\n") + "\n"
+	return styledText{
+		text:    "```" + language + "\n" + escape(code, codeEscapes) + "```",
+		escaped: true,
+	}
+}
+
+func (c *commonMark) inline(input string) styledText {
+	p := inlineParser{commonMark: c, input: input}
+	return p.parse()
+}
+
+type inlineParser struct {
+	*commonMark
+	input string
+	pos   int
+	text  []byte
+	out   []styledText
+}
+
+func (p *inlineParser) parse() styledText {
+	for p.pos < len(p.input) {
+		p.step()
+	}
+	p.flush()
+	return Combine(p.out...)
+}
+
+func (p *inlineParser) flush() {
+	if len(p.text) > 0 {
+		p.out = append(p.out, Text(string(p.text)))
+		p.text = p.text[:0]
+	}
+}
+
+func (p *inlineParser) emit(styled styledText) {
+	p.flush()
+	p.out = append(p.out, styled)
+}
+
+func (p *inlineParser) step() {
+	rest := p.input[p.pos:]
+	switch ch := rest[0]; ch {
+	case '\\':
+		switch {
+		case len(rest) > 1 && rest[1] == '\n':
+			p.lineBreak(true)
+			p.pos += 2
+		case len(rest) > 1 && strings.IndexByte(asciiPunctuation, rest[1]) >= 0:
+			p.text = append(p.text, rest[1])
+			p.pos += 2
+		default:
+			p.text = append(p.text, ch)
+			p.pos++
+		}
+		return
+	case '\n':
+		hard := strings.HasSuffix(string(p.text), "  ")
+		p.lineBreak(hard)
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+			p.pos++
+		}
+		return
+	case '`':
+		if p.codeSpan() {
+			return
+		}
+	case '*', '_', '~':
+		if p.emphasis(ch) {
+			return
+		}
+	case '!':
+		if strings.HasPrefix(rest, "![") && p.link(p.pos+1) {
+			return
+		}
+	case '[':
+		if p.link(p.pos) {
+			return
+		}
+	case '<':
+		if p.angle() {
+			return
+		}
+	case '&':
+		if m := entity.FindString(rest); m != "" {
+			p.text = append(p.text, html.UnescapeString(m)...)
+			p.pos += len(m)
+			return
+		}
+	}
+	p.text = append(p.text, p.input[p.pos])
+	p.pos++
+}
+
+func (p *inlineParser) lineBreak(hard bool) {
+	p.text = []byte(strings.TrimRight(string(p.text), " "))
+	if hard || p.options.HardLineBreaks {
+		p.text = append(p.text, '\n')
+	} else {
+		p.text = append(p.text, ' ')
+	}
+}
+
+func (p *inlineParser) codeSpan() bool {
+	n := runLength(p.input, p.pos)
+	end := findCodeSpanEnd(p.input, p.pos+n, n)
+	if end < 0 {
+		p.text = append(p.text, p.input[p.pos:p.pos+n]...)
+		p.pos += n
+		return true
+	}
+	code := strings.Replace(p.input[p.pos+n:end], "\n", " ", -1)
+	if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+	p.emit(InlineFixWidth(code))
+	p.pos = end + n
+	return true
+}
+
+func (p *inlineParser) emphasis(ch byte) bool {
+	n := runLength(p.input, p.pos)
+	limit := 3
+	if ch == '~' {
+		limit = 2
+	}
+	after := p.pos + n
+	if n > limit {
+		p.text = append(p.text, p.input[p.pos:after]...)
+		p.pos = after
+		return true
+	}
+	if after >= len(p.input) || isSpace(p.input[after]) {
+		return false
+	}
+	if ch == '_' && p.pos > 0 && isWordByte(p.input[p.pos-1]) {
+		return false
+	}
+	end := findCloser(p.input, after, ch, n)
+	if end < 0 {
+		p.text = append(p.text, p.input[p.pos:after]...)
+		p.pos = after
+		return true
+	}
+
+	content := p.inline(p.input[after:end])
+	switch {
+	case ch == '~':
+		p.emit(enclose(strikethroughNode, content))
+	case n == 1:
+		p.emit(enclose(italicNode, content))
+	case n == 2:
+		p.emit(enclose(boldNode, content))
+	default:
+		p.emit(enclose(boldNode, enclose(italicNode, content)))
+	}
+	p.pos = end + n
+	return true
+}
+
+// link parses an inline, full, collapsed or shortcut reference link starting
+// at the opening bracket. Images are converted to links to the image, with
+// the alternative text as the text of the link.
+func (p *inlineParser) link(open int) bool {
+	close := findBracket(p.input, open)
+	if close < 0 {
+		return false
+	}
+	label := p.input[open+1 : close]
+	url, end, ok := p.destination(close + 1)
+	if !ok {
+		url, end, ok = p.reference(label, close+1)
+	}
+	if !ok {
+		return false
+	}
+
+	content := p.inline(label)
+	if strings.TrimSpace(content.text) == "" {
+		content = Text(url)
+	}
+	p.emit(Link(url, content))
+	p.pos = end
+	return true
+}
+
+func (p *inlineParser) destination(start int) (string, int, bool) {
+	if start >= len(p.input) || p.input[start] != '(' {
+		return "", 0, false
+	}
+	i := start + 1
+	for i < len(p.input) && (p.input[i] == ' ' || p.input[i] == '\n') {
+		i++
+	}
+	var url string
+	if i < len(p.input) && p.input[i] == '<' {
+		end := strings.IndexAny(p.input[i:], ">\n")
+		if end < 0 || p.input[i+end] != '>' {
+			return "", 0, false
+		}
+		url, i = p.input[i+1:i+end], i+end+1
+	} else {
+		from, depth := i, 0
+	scan:
+		for ; i < len(p.input); i++ {
+			switch p.input[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break scan
+				}
+				depth--
+			case ' ', '\n', '\t':
+				break scan
+			}
+		}
+		if i > len(p.input) {
+			i = len(p.input)
+		}
+		url = p.input[from:i]
+	}
+	title := linkTitle.FindString(p.input[i:])
+	if title == "" {
+		return "", 0, false
+	}
+	return unescapeBackslashes(url), i + len(title), true
+}
+
+func (p *inlineParser) reference(label string, start int) (string, int, bool) {
+	end := start
+	if strings.HasPrefix(p.input[start:], "[") {
+		if close := findBracket(p.input, start); close >= 0 {
+			if ref := p.input[start+1 : close]; ref != "" {
+				label = ref
+			}
+			end = close + 1
+		}
+	}
+	url, ok := p.references[referenceLabel(label)]
+	return url, end, ok
+}
+
+// angle handles autolinks and strips raw HTML, turning <br> into a line
+// break.
+func (p *inlineParser) angle() bool {
+	rest := p.input[p.pos:]
+	if m := uriAutolink.FindStringSubmatch(rest); m != nil {
+		p.emit(InlineURL(m[1], m[1]))
+		p.pos += len(m[0])
+		return true
+	}
+	if m := emailAutolink.FindStringSubmatch(rest); m != nil {
+		p.emit(InlineURL(m[1], "mailto:"+m[1]))
+		p.pos += len(m[0])
+		return true
+	}
+	if m := htmlComment.FindString(rest); m != "" {
+		p.pos += len(m)
+		return true
+	}
+	if m := htmlTag.FindStringSubmatch(rest); m != nil {
+		if strings.EqualFold(m[1], "br") {
+			p.text = append(p.text, '\n')
+		}
+		p.pos += len(m[0])
+		return true
+	}
+	return false
+}
+
+func runLength(input string, pos int) int {
+	n := 1
+	for pos+n < len(input) && input[pos+n] == input[pos] {
+		n++
+	}
+	return n
+}
+
+func findCodeSpanEnd(input string, from, n int) int {
+	for i := from; i < len(input); {
+		if input[i] != '`' {
+			i++
+			continue
+		}
+		run := runLength(input, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// findCloser returns the position of the delimiter run of the same length
+// that closes the emphasis opened before from, skipping escapes and code
+// spans.
+func findCloser(input string, from int, ch byte, n int) int {
+	for i := from; i < len(input); {
+		switch input[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			run := runLength(input, i)
+			if end := findCodeSpanEnd(input, i+run, run); end >= 0 {
+				i = end + run
+				continue
+			}
+			i += run
+			continue
+		case ch:
+			run := runLength(input, i)
+			closes := run == n && !isSpace(input[i-1])
+			if ch == '_' && i+run < len(input) && isWordByte(input[i+run]) {
+				closes = false
+			}
+			if closes {
+				return i
+			}
+			i += run
+			continue
+		}
+		i++
+	}
+	return -1
+}
+
+func findBracket(input string, open int) int {
+	depth := 0
+	for i := open; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '`':
+			run := runLength(input, i)
+			if end := findCodeSpanEnd(input, i+run, run); end >= 0 {
+				i = end + run - 1
+			} else {
+				i += run - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, cell.String())
+}
+
+func isDelimiterRow(line string) bool {
+	if !strings.Contains(line, "-") {
+		return false
+	}
+	for _, cell := range splitTableRow(line) {
+		if !tableDelimiter.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBlockStart(line string) bool {
+	return atxHeading.MatchString(line) || thematicBreak.MatchString(line) || codeFence.MatchString(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">") || listMarker.MatchString(line)
+}
+
+// canInterrupt reports whether a list item may start in the middle of a
+// paragraph: it may not be empty, and an ordered list has to start at one.
+func canInterrupt(line string) bool {
+	m := listMarker.FindStringSubmatch(line)
+	if strings.TrimSpace(line[len(m[0]):]) == "" {
+		return false
+	}
+	marker := m[2]
+	return !(marker[0] >= '0' && marker[0] <= '9') || marker[:len(marker)-1] == "1"
+}
+
+func sameListType(a, b string) bool {
+	if a[0] >= '0' && a[0] <= '9' {
+		return b[0] >= '0' && b[0] <= '9' && a[len(a)-1] == b[len(b)-1]
+	}
+	return a == b
+}
+
+func containsBlankLine(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func referenceLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+func unescapeBackslashes(input string) string {
+	if strings.IndexByte(input, '\\') < 0 {
+		return input
+	}
+	var out strings.Builder
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\\' && i+1 < len(input) && strings.IndexByte(asciiPunctuation, input[i+1]) >= 0 {
+			i++
+		}
+		out.WriteByte(input[i])
+	}
+	return out.String()
+}
+
+func expandIndent(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var out strings.Builder
+	column := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			out.WriteByte(' ')
+			column++
+		case '\t':
+			width := 4 - column%4
+			out.WriteString(strings.Repeat(" ", width))
+			column += width
+		default:
+			out.WriteString(line[i:])
+			return out.String()
+		}
+	}
+	return out.String()
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func trimIndent(line string, n int) string {
+	if indent := indentation(line); indent < n {
+		n = indent
+	}
+	return line[n:]
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n'
+}
+
+func isWordByte(ch byte) bool {
+	r := rune(ch)
+	return ch >= utf8.RuneSelf || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
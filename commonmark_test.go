@@ -0,0 +1,154 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestFromCommonMarkInline(t *testing.T) {
+	got := md.FromCommonMark("Some *emphasis*, __strong__, ***both***, ~~gone~~ and `a_b`.")
+	want := "Some _emphasis_, *strong*, *_both_*, ~gone~ and `a_b`\\."
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkIntrawordUnderscore(t *testing.T) {
+	got := md.FromCommonMark("snake_case_name and 2*3*4")
+	want := "snake\\_case\\_name and 2_3_4"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkLineBreaks(t *testing.T) {
+	input := "soft\nbreak and hard  \nbreak\\\nagain"
+
+	got := md.FromCommonMark(input).String()
+	want := "soft break and hard\nbreak\nagain"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+
+	got = md.CommonMarkOptions{HardLineBreaks: true}.Convert(input).String()
+	want = "soft\nbreak and hard\nbreak\nagain"
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestFromCommonMarkLinks(t *testing.T) {
+	input := "[docs](https://example.com/a_(b) \"Title\"), ![logo](logo.png), [ref][r], [r], " +
+		"<https://auto.link> and <me@example.com>\n\n[r]: https://ref.example"
+
+	got := md.FromCommonMark(input)
+	want := "[docs](https://example.com/a_(b\\)), [logo](logo.png), [ref](https://ref.example), " +
+		"[r](https://ref.example), [https://auto\\.link](https://auto.link) and " +
+		"[me@example\\.com](mailto:me@example.com)"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkHeadings(t *testing.T) {
+	got := md.FromCommonMark("# Title #\n\nSetext\n------\ntext")
+	want := "__*TITLE*__\n__*Setext*__\ntext"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkNestedEmphasis(t *testing.T) {
+	got := md.FromCommonMark("*a _b_ c* **a __b__ c** ***a *b* c***")
+	want := "_a b c_ *a b c* *_a b c_*"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkEmphasisInHeadings(t *testing.T) {
+	got := md.FromCommonMark("# **Important** note\n#### an *it*")
+	want := "__*IMPORTANT NOTE*__\n___an it_\r__"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkCodeBlocks(t *testing.T) {
+	got := md.FromCommonMark("```go\nfmt.Println(\"`hi`\")\n```\n\n    indented\n    code")
+	want := "```go\nfmt.Println(\"\\`hi\\`\")\n```\n\n```\nindented\ncode\n```"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkLists(t *testing.T) {
+	input := "- [x] done\n- [ ] todo\n  - nested *item*\n- last\n\n3. three\n4. four"
+
+	got := md.FromCommonMark(input)
+	want := "• ☑ done\n• ☐ todo\n  • nested _item_\n• last\n\n3\\. three\n4\\. four"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkBlockquote(t *testing.T) {
+	got := md.FromCommonMark("> quoted **text**\nlazy line\n>\n> second paragraph")
+	want := ">quoted *text* lazy line\n>\n>second paragraph"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkTable(t *testing.T) {
+	input := "| Name | Count | State |\n|:-----|------:|:-----:|\n| **a** | 1 | ok |\n| b\\|c | 22 | `failed` |"
+
+	got := md.FromCommonMark(input)
+	want := "```\nName | Count | State \n" +
+		"a    |     1 |   ok  \n" +
+		"b|c  |    22 | failed\n```"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkStripsHTML(t *testing.T) {
+	input := "<!-- template\ncomment -->\n<details>\n<summary>More</summary>\n</details>\n\n" +
+		"Press <kbd>Ctrl</kbd><br>then &lt;Enter&gt; &amp; wait"
+
+	got := md.FromCommonMark(input)
+	want := "More\n\nPress Ctrl\nthen <Enter\\> & wait"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkRule(t *testing.T) {
+	got := md.FromCommonMark("above\n\n***\n\nbelow")
+	want := "above\n\n────────────────\n\nbelow"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromCommonMarkListStartingAtZero(t *testing.T) {
+	got := md.FromCommonMark("0. zero\n1. one")
+	want := "0\\. zero\n1\\. one"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
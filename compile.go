@@ -0,0 +1,88 @@
+package telegrammarkdown
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+const slotMarker = "\x00"
+
+var errUnterminatedSlot = errors.New("unterminated slot in compiled template")
+
+func Slot(name string) styledText {
+	return styledText{text: slotMarker + name + slotMarker, escaped: true}
+}
+
+type compiledSlot struct {
+	name    string
+	escaper *escaper
+}
+
+type CompiledTemplate struct {
+	literals []string
+	slots    []compiledSlot
+	size     int
+}
+
+func Compile(template styledText) (*CompiledTemplate, error) {
+	template.escape()
+	parts := strings.Split(template.text, slotMarker)
+	if len(parts)%2 == 0 {
+		return nil, errUnterminatedSlot
+	}
+
+	compiled := &CompiledTemplate{}
+	c := contextText
+	for i, part := range parts {
+		if i%2 == 1 {
+			compiled.slots = append(compiled.slots, compiledSlot{name: part, escaper: escaperFor(c.charset())})
+			continue
+		}
+		compiled.literals = append(compiled.literals, part)
+		compiled.size += len(part)
+		c = c.advance(part)
+	}
+	return compiled, nil
+}
+
+func MustCompile(template styledText) *CompiledTemplate {
+	compiled, err := Compile(template)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+func (t *CompiledTemplate) Slots() []string {
+	names := make([]string, len(t.slots))
+	for i, slot := range t.slots {
+		names[i] = slot.name
+	}
+	return names
+}
+
+func (t *CompiledTemplate) Render(values map[string]string) styledText {
+	var out strings.Builder
+	size := t.size
+	for _, slot := range t.slots {
+		size += len(values[slot.name]) + len(values[slot.name])/8
+	}
+	out.Grow(size)
+	t.render(&out, values)
+	return styledText{text: out.String(), escaped: true}
+}
+
+func (t *CompiledTemplate) RenderTo(w io.Writer, values map[string]string) (int64, error) {
+	out := &renderWriter{w: w}
+	t.render(out, values)
+	return out.n, out.err
+}
+
+func (t *CompiledTemplate) render(out io.StringWriter, values map[string]string) {
+	for i, slot := range t.slots {
+		out.WriteString(t.literals[i])
+		slot.escaper.write(out, values[slot.name])
+	}
+	out.WriteString(t.literals[len(t.literals)-1])
+}
@@ -0,0 +1,93 @@
+package telegrammarkdown_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+var deployValues = map[string]string{
+	"user":    "john.doe",
+	"version": "v1.2-rc",
+	"env":     "prod`eu",
+	"url":     "https://x.io/a)b",
+}
+
+func compileDeployTemplate() *md.CompiledTemplate {
+	return md.MustCompile(md.CombineWithSpace(
+		md.Bold(md.Slot("user")),
+		md.Text("deployed"),
+		md.Slot("version"),
+		md.Text("to"),
+		md.InlineFixWidth(md.Slot("env").String()),
+		md.InlineURL("details", md.Slot("url").String()),
+	))
+}
+
+func buildDeployMessage(values map[string]string) string {
+	return md.CombineWithSpace(
+		md.BoldText(values["user"]),
+		md.Text("deployed"),
+		md.Text(values["version"]),
+		md.Text("to"),
+		md.InlineFixWidth(values["env"]),
+		md.InlineURL("details", values["url"]),
+	).String()
+}
+
+func TestCompiledTemplateRender(t *testing.T) {
+	got := compileDeployTemplate().Render(deployValues)
+	want := buildDeployMessage(deployValues)
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestCompiledTemplateSlots(t *testing.T) {
+	got := strings.Join(compileDeployTemplate().Slots(), ",")
+	want := "user,version,env,url"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestCompiledTemplateRenderTo(t *testing.T) {
+	var out strings.Builder
+	if _, err := compileDeployTemplate().RenderTo(&out, deployValues); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	want := buildDeployMessage(deployValues)
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestCompileUnterminatedSlot(t *testing.T) {
+	if _, err := md.Compile(md.Combine(md.Text("a"), md.Slot("x"), md.Slot("y"))); err != nil {
+		t.Fatal(err)
+	}
+	broken := md.Slot("x").String()[:2]
+	if _, err := md.Compile(md.Text(broken)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func BenchmarkCompiledTemplateRender(b *testing.B) {
+	compiled := compileDeployTemplate()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		compiled.Render(deployValues)
+	}
+}
+
+func BenchmarkBuildersRender(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buildDeployMessage(deployValues)
+	}
+}
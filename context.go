@@ -0,0 +1,101 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+type escapeContext int
+
+const (
+	contextText escapeContext = iota
+	contextLinkEnd
+	contextLinkURL
+	contextCode
+	contextPre
+)
+
+var contextNames = map[escapeContext]string{
+	contextText:    "text",
+	contextLinkEnd: "link end",
+	contextLinkURL: "link URL",
+	contextCode:    "inline code",
+	contextPre:     "code block",
+}
+
+func (c escapeContext) String() string {
+	return contextNames[c]
+}
+
+// advance returns the context at the end of trusted MarkdownV2 text that
+// starts in context c.
+func (c escapeContext) advance(text string) escapeContext {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			if c == contextLinkEnd {
+				c = contextText
+			}
+			continue
+		}
+		switch c {
+		case contextText, contextLinkEnd:
+			switch {
+			case c == contextLinkEnd && text[i] == '(':
+				c = contextLinkURL
+			case strings.HasPrefix(text[i:], "```"):
+				c = contextPre
+				i += 2
+			case text[i] == '`':
+				c = contextCode
+			case text[i] == ']':
+				c = contextLinkEnd
+			default:
+				c = contextText
+			}
+		case contextLinkURL:
+			if text[i] == ')' {
+				c = contextText
+			}
+		case contextCode:
+			if text[i] == '`' {
+				c = contextText
+			}
+		case contextPre:
+			if strings.HasPrefix(text[i:], "```") {
+				c = contextText
+				i += 2
+			}
+		}
+	}
+	return c
+}
+
+func (c escapeContext) escape(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case styledText:
+		if c == contextText || c == contextLinkEnd {
+			v.escape()
+			return v.text
+		}
+		text = Plain(v)
+	case string:
+		text = v
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		text = fmt.Sprint(v)
+	}
+	return escape(text, c.charset())
+}
+
+func (c escapeContext) charset() string {
+	switch c {
+	case contextLinkURL:
+		return urlEscapes
+	case contextCode, contextPre:
+		return codeEscapes
+	}
+	return escapes
+}
@@ -0,0 +1,282 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type diffOp struct {
+	kind    string
+	text    string
+	oldLine int
+	newLine int
+}
+
+type DiffOptions struct {
+	Context   int
+	WordLevel bool
+	OldName   string
+	NewName   string
+	MaxLines  int
+}
+
+func Diff(old, current string, context int) styledText {
+	return DiffOptions{Context: context}.Diff(old, current)
+}
+
+func (o DiffOptions) Diff(old, current string) styledText {
+	ops := diffSequences(splitLines(old), splitLines(current))
+	if o.WordLevel {
+		markWords(ops)
+	}
+
+	var lines []string
+	if o.OldName != "" || o.NewName != "" {
+		lines = append(lines, "--- "+o.OldName, "+++ "+o.NewName)
+	}
+	for _, hunk := range hunks(ops, o.Context) {
+		lines = append(lines, hunkHeader(hunk))
+		for _, op := range hunk {
+			lines = append(lines, op.kind+op.text)
+		}
+	}
+	if o.MaxLines > 0 && len(lines) > o.MaxLines {
+		hidden := len(lines) - o.MaxLines
+		lines = append(lines[:o.MaxLines], fmt.Sprintf("… %d more lines", hidden))
+	}
+	if len(lines) == 0 {
+		return CodeBlock("diff", "")
+	}
+	return CodeBlock("diff", strings.Join(lines, "\n")+"\n")
+}
+
+func splitLines(input string) []string {
+	if input == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(lineEndings.Replace(input), "\n"), "\n")
+}
+
+// maxDiffCells limits the size of the table diffSequences builds. Changes
+// larger than that are shown as a removal of all old lines followed by an
+// addition of all new ones.
+const maxDiffCells = 1 << 22
+
+// diffSequences diffs a and b, leaving their common prefix and suffix out of
+// the table of longest common subsequences.
+func diffSequences(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: diffUnchanged, text: a[i], oldLine: i, newLine: i})
+	}
+	middle := lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, op := range middle {
+		op.oldLine += prefix
+		op.newLine += prefix
+		ops = append(ops, op)
+	}
+	for k := suffix; k > 0; k-- {
+		i, j := len(a)-k, len(b)-k
+		ops = append(ops, diffOp{kind: diffUnchanged, text: a[i], oldLine: i, newLine: j})
+	}
+	return ops
+}
+
+func lcsDiff(a, b []string) []diffOp {
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		ops := make([]diffOp, 0, len(a)+len(b))
+		for i, text := range a {
+			ops = append(ops, diffOp{kind: diffRemoved, text: text, oldLine: i})
+		}
+		for j, text := range b {
+			ops = append(ops, diffOp{kind: diffAdded, text: text, oldLine: len(a), newLine: j})
+		}
+		return ops
+	}
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: diffUnchanged, text: a[i], oldLine: i, newLine: j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			ops = append(ops, diffOp{kind: diffAdded, text: b[j], oldLine: i, newLine: j})
+			j++
+		default:
+			ops = append(ops, diffOp{kind: diffRemoved, text: a[i], oldLine: i, newLine: j})
+			i++
+		}
+	}
+	return reorderChanges(ops)
+}
+
+// reorderChanges moves removals ahead of the additions they are interleaved
+// with, so that every change reads as a "-" block followed by a "+" block.
+func reorderChanges(ops []diffOp) []diffOp {
+	for start := 0; start < len(ops); {
+		if ops[start].kind == diffUnchanged {
+			start++
+			continue
+		}
+		end := start
+		for end < len(ops) && ops[end].kind != diffUnchanged {
+			end++
+		}
+		var removed, added []diffOp
+		for _, op := range ops[start:end] {
+			if op.kind == diffRemoved {
+				removed = append(removed, op)
+			} else {
+				added = append(added, op)
+			}
+		}
+		copy(ops[start:], append(removed, added...))
+		start = end
+	}
+	return ops
+}
+
+func hunks(ops []diffOp, context int) [][]diffOp {
+	if context < 0 {
+		context = 0
+	}
+	var result [][]diffOp
+	for i := 0; i < len(ops); {
+		if ops[i].kind == diffUnchanged {
+			i++
+			continue
+		}
+		start := maxInt(0, i-context)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != diffUnchanged {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == diffUnchanged {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end = minInt(len(ops), end+context)
+				break
+			}
+			end = next
+		}
+		result = append(result, ops[start:end])
+		i = end
+	}
+	return result
+}
+
+func hunkHeader(hunk []diffOp) string {
+	oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+	oldCount, newCount := 0, 0
+	for _, op := range hunk {
+		oldStart = minInt(oldStart, op.oldLine)
+		newStart = minInt(newStart, op.newLine)
+		if op.kind != diffAdded {
+			oldCount++
+		}
+		if op.kind != diffRemoved {
+			newCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%s +%s @@",
+		hunkRange(oldStart, oldCount),
+		hunkRange(newStart, newCount),
+	)
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func markWords(ops []diffOp) {
+	for start := 0; start < len(ops); {
+		if ops[start].kind != diffRemoved {
+			start++
+			continue
+		}
+		removed := start
+		for removed < len(ops) && ops[removed].kind == diffRemoved {
+			removed++
+		}
+		added := removed
+		for added < len(ops) && ops[added].kind == diffAdded {
+			added++
+		}
+		pairs := minInt(removed-start, added-removed)
+		for k := 0; k < pairs; k++ {
+			before, after := &ops[start+k], &ops[removed+k]
+			before.text, after.text = wordDiff(before.text, after.text)
+		}
+		start = added
+	}
+}
+
+func wordDiff(old, current string) (string, string) {
+	var marked [2]strings.Builder
+	for _, op := range diffSequences(splitWords(old), splitWords(current)) {
+		switch op.kind {
+		case diffUnchanged:
+			marked[0].WriteString(op.text)
+			marked[1].WriteString(op.text)
+		case diffRemoved:
+			marked[0].WriteString("[-" + op.text + "-]")
+		case diffAdded:
+			marked[1].WriteString("{+" + op.text + "+}")
+		}
+	}
+	return marked[0].String(), marked[1].String()
+}
+
+func splitWords(input string) []string {
+	var words []string
+	start, space := 0, false
+	for i, ch := range input {
+		if i > start && unicode.IsSpace(ch) != space {
+			words = append(words, input[start:i])
+			start = i
+		}
+		space = unicode.IsSpace(ch)
+	}
+	if start < len(input) {
+		words = append(words, input[start:])
+	}
+	return words
+}
@@ -0,0 +1,116 @@
+package telegrammarkdown_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\n"
+	current := "a\nb\nC\nd\ne\nf\ng\nh\n"
+
+	got := md.Diff(old, current, 1)
+	want := "```diff\n"
+	want += "@@ -2,3 +2,3 @@\n"
+	want += " b\n"
+	want += "-c\n"
+	want += "+C\n"
+	want += " d\n"
+	want += "@@ -7 +7,2 @@\n"
+	want += " g\n"
+	want += "+h\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestDiffMergesCloseHunks(t *testing.T) {
+	got := md.Diff("a\nb\nc\n", "x\nb\ny\n", 1)
+	want := "```diff\n"
+	want += "@@ -1,3 +1,3 @@\n"
+	want += "-a\n"
+	want += "+x\n"
+	want += " b\n"
+	want += "-c\n"
+	want += "+y\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestDiffWordLevel(t *testing.T) {
+	options := md.DiffOptions{WordLevel: true, OldName: "a.conf", NewName: "b.conf"}
+
+	got := options.Diff("port = 80\n", "port = 8080\n")
+	want := "```diff\n"
+	want += "--- a.conf\n"
+	want += "+++ b.conf\n"
+	want += "@@ -1 +1 @@\n"
+	want += "-port = [-80-]\n"
+	want += "+port = {+8080+}\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestDiffEscapes(t *testing.T) {
+	got := md.Diff("`a`\n", "\\b\n", 0)
+	want := "```diff\n"
+	want += "@@ -1 +1 @@\n"
+	want += "-\\`a\\`\n"
+	want += "+\\\\b\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestDiffMaxLines(t *testing.T) {
+	options := md.DiffOptions{MaxLines: 2}
+
+	got := options.Diff("a\nb\n", "c\nd\n")
+	want := "```diff\n"
+	want += "@@ -1,2 +1,2 @@\n"
+	want += "-a\n"
+	want += "… 3 more lines\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestDiffLargeChange(t *testing.T) {
+	var old, current strings.Builder
+	old.WriteString("x\n")
+	current.WriteString("x\n")
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&old, "a%d\n", i)
+		fmt.Fprintf(&current, "b%d\n", i)
+	}
+	old.WriteString("z\n")
+	current.WriteString("z\n")
+
+	got := md.DiffOptions{Context: 1, MaxLines: 4}.Diff(old.String(), current.String())
+	want := "```diff\n"
+	want += "@@ -1,3002 +1,3002 @@\n"
+	want += " x\n"
+	want += "-a0\n"
+	want += "-a1\n"
+	want += "… 5999 more lines\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
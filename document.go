@@ -0,0 +1,173 @@
+package telegrammarkdown
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type HeadingStyle struct {
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Uppercase bool
+	Prefix    string
+}
+
+var HeadingStyles = map[int]HeadingStyle{
+	1: {Bold: true, Underline: true, Uppercase: true},
+	2: {Bold: true, Underline: true},
+	3: {Bold: true},
+	4: {Italic: true, Underline: true},
+	5: {Italic: true},
+	6: {Underline: true},
+}
+
+func (h HeadingStyle) Apply(input ...styledText) styledText {
+	heading := Combine(input...)
+	if h.Uppercase {
+		heading.text = upperVisible(heading.text)
+	}
+	if h.Italic {
+		heading = enclose(italicNode, heading)
+	}
+	if h.Bold {
+		heading = enclose(boldNode, heading)
+	}
+	if h.Underline {
+		heading = enclose(underlineNode, heading)
+	}
+	if h.Prefix != "" {
+		heading = Combine(Text(h.Prefix), Space(), heading)
+	}
+	return heading
+}
+
+func Heading(level int, input ...styledText) styledText {
+	return headingStyle(HeadingStyles, level).Apply(input...)
+}
+
+func HeadingText(level int, input string) styledText {
+	return Heading(level, Text(input))
+}
+
+func headingStyle(styles map[int]HeadingStyle, level int) HeadingStyle {
+	if style, ok := styles[level]; ok {
+		return style
+	}
+	return HeadingStyle{Bold: true}
+}
+
+func Paragraph(input ...styledText) styledText {
+	return Combine(input...)
+}
+
+func HorizontalRule(char string, width int) styledText {
+	if char == "" {
+		char = "─"
+	}
+	if width <= 0 {
+		width = 16
+	}
+	count := width / utf8.RuneCountInString(char)
+	if count == 0 {
+		count = 1
+	}
+	return Text(strings.Repeat(char, count))
+}
+
+type blockKind int
+
+const (
+	plainBlock blockKind = iota
+	headingBlock
+)
+
+type documentBlock struct {
+	kind blockKind
+	text styledText
+}
+
+type Document struct {
+	HeadingStyles map[int]HeadingStyle
+	RuleChar      string
+	RuleWidth     int
+	blocks        []documentBlock
+}
+
+func (d *Document) Add(blocks ...styledText) {
+	for _, block := range blocks {
+		block.escape()
+		d.blocks = append(d.blocks, documentBlock{kind: plainBlock, text: block})
+	}
+}
+
+func (d *Document) AddHeading(level int, input ...styledText) {
+	styles := d.HeadingStyles
+	if styles == nil {
+		styles = HeadingStyles
+	}
+	heading := headingStyle(styles, level).Apply(input...)
+	d.blocks = append(d.blocks, documentBlock{kind: headingBlock, text: heading})
+}
+
+func (d *Document) AddParagraph(input ...styledText) {
+	d.Add(Paragraph(input...))
+}
+
+func (d *Document) AddRule() {
+	d.Add(HorizontalRule(d.RuleChar, d.RuleWidth))
+}
+
+func (d *Document) AddTable(table *Table) {
+	d.Add(table.Styled())
+}
+
+func (d *Document) String() string {
+	return d.Styled().String()
+}
+
+func (d *Document) Styled() styledText {
+	size := 0
+	for _, block := range d.blocks {
+		size += len(block.text.text) + 2
+	}
+	var text strings.Builder
+	text.Grow(size)
+	d.render(&text)
+	return styledText{text: text.String(), escaped: true}
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	out := &renderWriter{w: w}
+	d.render(out)
+	return out.n, out.err
+}
+
+func (d *Document) render(w io.StringWriter) {
+	for i, block := range d.blocks {
+		if i > 0 {
+			w.WriteString(newLine)
+			if d.blocks[i-1].kind != headingBlock {
+				w.WriteString(newLine)
+			}
+		}
+		w.WriteString(block.text.text)
+	}
+}
+
+func upperVisible(input string) string {
+	nodes := parseNodes(input)
+	upperNodes(nodes)
+	return render(nodes)
+}
+
+// upperNodes uppercases text, leaving code and link URLs as they are.
+func upperNodes(nodes []*node) {
+	for _, n := range nodes {
+		if n.kind == textNode {
+			n.text = strings.ToUpper(n.text)
+		}
+		upperNodes(n.children)
+	}
+}
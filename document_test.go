@@ -0,0 +1,96 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestHeading(t *testing.T) {
+	got := md.HeadingText(1, "release notes")
+	want := `__*RELEASE NOTES*__`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestHeadingKeepsURL(t *testing.T) {
+	got := md.Heading(1, md.InlineURL("docs", "golang.org/Doc"))
+	want := `__*[DOCS](golang.org/Doc)*__`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestHeadingKeepsCode(t *testing.T) {
+	got := md.Heading(1, md.Text("run "), md.InlineFixWidth("code"))
+	want := "__*RUN `code`*__"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestHeadingStylePrefix(t *testing.T) {
+	got := md.HeadingStyle{Bold: true, Prefix: "🚀"}.Apply(md.Text("deploy"))
+	want := `🚀 *deploy*`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestHorizontalRule(t *testing.T) {
+	got := md.HorizontalRule("-", 4)
+	want := `\-\-\-\-`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := md.Document{RuleChar: "=", RuleWidth: 3}
+	doc.AddHeading(3, md.Text("title"))
+	doc.AddParagraph(md.Text("first "), md.BoldText("paragraph"))
+	doc.AddRule()
+	doc.AddParagraph(md.Text("second"))
+
+	got := doc.String()
+	want := "*title*\nfirst *paragraph*\n\n\\=\\=\\=\n\nsecond"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestHeadingWithStyledContent(t *testing.T) {
+	got := md.Heading(2, md.Text("a "), md.BoldText("b"))
+	want := "__*a b*__"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestItalicUnderlineHeadingIsUnambiguous(t *testing.T) {
+	got := md.HeadingText(4, "x")
+	want := "___x_\r__"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestItalicUnderlineHeadingSurvivesTruncate(t *testing.T) {
+	heading := md.HeadingText(4, "x")
+
+	got := md.Truncate(heading, 10, "…")
+	want := heading.String()
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
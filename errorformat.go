@@ -0,0 +1,130 @@
+package telegrammarkdown
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	frameArguments = regexp.MustCompile(`\(((0x[0-9a-f]+|\.\.\.|\{[^}]*\}|\?)(, )?)+\)$`)
+	frameOffset    = regexp.MustCompile(` \+0x[0-9a-f]+$`)
+)
+
+type stackFrame struct {
+	function string
+	location string
+}
+
+type ErrorFormat struct {
+	Title     string
+	Modules   []string
+	MaxFrames int
+}
+
+func FormatError(err error, stack []byte) styledText {
+	return ErrorFormat{}.Format(err, stack)
+}
+
+func (f ErrorFormat) Format(err error, stack []byte) styledText {
+	var blocks []styledText
+	if err != nil {
+		summary := strings.SplitN(err.Error(), "\n", 2)[0]
+		if f.Title != "" {
+			summary = f.Title + ": " + summary
+		}
+		blocks = append(blocks, BoldText(summary))
+		if causes := errorChain(err); len(causes) > 1 {
+			blocks = append(blocks, BulletList(causes...))
+		}
+	} else if f.Title != "" {
+		blocks = append(blocks, BoldText(f.Title))
+	}
+	if frames := parseStack(string(stack)); len(frames) > 0 {
+		blocks = append(blocks, CodeBlock("", f.trace(frames)))
+	}
+	return CombineWithNewLine(blocks...)
+}
+
+func errorChain(err error) []styledText {
+	var chain []styledText
+	for err != nil {
+		message := err.Error()
+		next := errors.Unwrap(err)
+		if next != nil {
+			message = strings.TrimSuffix(message, ": "+next.Error())
+		}
+		chain = append(chain, CombineWithSpace(
+			Text(message),
+			InlineFixWidth(fmt.Sprintf("%T", err)),
+		))
+		err = next
+	}
+	return chain
+}
+
+func parseStack(stack string) []stackFrame {
+	var frames []stackFrame
+	lines := strings.Split(lineEndings.Replace(stack), "\n")
+	for i := 0; i < len(lines)-1; i++ {
+		function, location := lines[i], lines[i+1]
+		if function == "" || strings.HasPrefix(function, "\t") || !strings.HasPrefix(location, "\t") {
+			continue
+		}
+		function = strings.TrimPrefix(function, "created by ")
+		function = frameArguments.ReplaceAllString(function, "(...)")
+		location = frameOffset.ReplaceAllString(strings.TrimSpace(location), "")
+		frames = append(frames, stackFrame{function: function, location: location})
+		i++
+	}
+	return frames
+}
+
+func (f ErrorFormat) highlighted(frame stackFrame) bool {
+	for _, module := range f.Modules {
+		if strings.HasPrefix(frame.function, module) {
+			return true
+		}
+	}
+	return false
+}
+
+func collapsible(frame stackFrame) bool {
+	return strings.HasPrefix(frame.function, "runtime.") ||
+		strings.HasPrefix(frame.function, "runtime/") ||
+		strings.Contains(frame.location, "/vendor/") ||
+		strings.Contains(frame.location, "/pkg/mod/")
+}
+
+func (f ErrorFormat) trace(frames []stackFrame) string {
+	var lines []string
+	shown, collapsed := 0, 0
+	flush := func() {
+		if collapsed > 0 {
+			lines = append(lines, fmt.Sprintf("  %s %d runtime/vendor frames", elision, collapsed))
+			collapsed = 0
+		}
+	}
+	for i, frame := range frames {
+		highlighted := f.highlighted(frame)
+		if !highlighted && collapsible(frame) {
+			collapsed++
+			continue
+		}
+		if f.MaxFrames > 0 && shown == f.MaxFrames {
+			flush()
+			lines = append(lines, fmt.Sprintf("  %s %d more frames", elision, len(frames)-i))
+			return strings.Join(lines, "\n") + "\n"
+		}
+		flush()
+		marker := "  "
+		if highlighted {
+			marker = "→ "
+		}
+		lines = append(lines, marker+frame.function, "      "+frame.location)
+		shown++
+	}
+	flush()
+	return strings.Join(lines, "\n") + "\n"
+}
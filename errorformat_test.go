@@ -0,0 +1,74 @@
+package telegrammarkdown_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+const sampleStack = `goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:24 +0x5e
+example.com/app/db.Query(0xc000010000, 0x2)
+	/src/app/db/query.go:42 +0x1d
+github.com/lib/pq.(*conn).query(...)
+	/go/pkg/mod/github.com/lib/pq@v1.0.0/conn.go:10 +0x2a
+main.main()
+	/src/app/main.go:8 +0x1d
+runtime.main()
+	/usr/local/go/src/runtime/proc.go:250 +0x212
+`
+
+func TestFormatErrorChain(t *testing.T) {
+	base := errors.New("connection refused")
+	err := fmt.Errorf("query users: %w", base)
+
+	got := md.FormatError(err, nil)
+	want := "*query users: connection refused*\n"
+	want += "• query users `*fmt.wrapError`\n"
+	want += "• connection refused `*errors.errorString`"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestFormatErrorStack(t *testing.T) {
+	format := md.ErrorFormat{Title: "panic", Modules: []string{"example.com/app"}}
+
+	got := format.Format(errors.New("boom"), []byte(sampleStack))
+	want := "*panic: boom*\n"
+	want += "```\n"
+	want += "  … 1 runtime/vendor frames\n"
+	want += "→ example.com/app/db.Query(...)\n"
+	want += "      /src/app/db/query.go:42\n"
+	want += "  … 1 runtime/vendor frames\n"
+	want += "  main.main()\n"
+	want += "      /src/app/main.go:8\n"
+	want += "  … 1 runtime/vendor frames\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestFormatErrorMaxFrames(t *testing.T) {
+	format := md.ErrorFormat{MaxFrames: 1}
+
+	got := format.Format(errors.New("boom"), []byte(sampleStack))
+	want := "*boom*\n"
+	want += "```\n"
+	want += "  … 1 runtime/vendor frames\n"
+	want += "  example.com/app/db.Query(...)\n"
+	want += "      /src/app/db/query.go:42\n"
+	want += "  … 1 runtime/vendor frames\n"
+	want += "  … 2 more frames\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
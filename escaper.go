@@ -0,0 +1,106 @@
+package telegrammarkdown
+
+import (
+	"io"
+	"strings"
+)
+
+// escaper is a lookup table of the ASCII characters to be prefixed with a
+// backslash.
+type escaper [256]bool
+
+var (
+	textEscaper = newEscaper(escapes)
+	codeEscaper = newEscaper(codeEscapes)
+	urlEscaper  = newEscaper(urlEscapes)
+	noEscaper   = newEscaper("")
+)
+
+const spaces = "                                "
+
+func newEscaper(charset string) *escaper {
+	e := &escaper{}
+	for i := 0; i < len(charset); i++ {
+		e[charset[i]] = true
+	}
+	return e
+}
+
+func escaperFor(charset string) *escaper {
+	switch charset {
+	case escapes:
+		return textEscaper
+	case codeEscapes:
+		return codeEscaper
+	case urlEscapes:
+		return urlEscaper
+	}
+	return newEscaper(charset)
+}
+
+func (e *escaper) count(input string) int {
+	n := 0
+	for i := 0; i < len(input); i++ {
+		if e[input[i]] {
+			n++
+		}
+	}
+	return n
+}
+
+func (e *escaper) escape(input string) string {
+	n := e.count(input)
+	if n == 0 {
+		return input
+	}
+	var out strings.Builder
+	out.Grow(len(input) + n)
+	for i := 0; i < len(input); i++ {
+		if e[input[i]] {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(input[i])
+	}
+	return out.String()
+}
+
+func (e *escaper) write(w io.StringWriter, input string) {
+	start := 0
+	for i := 0; i < len(input); i++ {
+		if !e[input[i]] {
+			continue
+		}
+		w.WriteString(input[start:i])
+		w.WriteString(`\`)
+		start = i
+	}
+	w.WriteString(input[start:])
+}
+
+func writeSpaces(w io.StringWriter, n int) {
+	for n > len(spaces) {
+		w.WriteString(spaces)
+		n -= len(spaces)
+	}
+	if n > 0 {
+		w.WriteString(spaces[:n])
+	}
+}
+
+// renderWriter adapts an io.Writer for the renderers, remembering the first
+// error and the number of bytes written.
+type renderWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (r *renderWriter) WriteString(s string) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := io.WriteString(r.w, s)
+	r.n += int64(n)
+	r.err = err
+	return n, err
+}
@@ -0,0 +1,173 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LegacyIssue is a construct of a legacy Markdown message that may not
+// convert the way its author intended. Offset is in bytes.
+type LegacyIssue struct {
+	Offset  int
+	Message string
+}
+
+func (i LegacyIssue) String() string {
+	return fmt.Sprintf("offset %d: %s", i.Offset, i.Message)
+}
+
+// FromLegacy converts a message written for parse_mode=Markdown to MarkdownV2.
+// Entities are parsed the way Telegram parses legacy Markdown: they cannot
+// nest and end at the next occurrence of their marker. Markers that are never
+// closed, which Telegram rejects, are kept as text.
+func FromLegacy(input string) (styledText, []LegacyIssue) {
+	p := legacyParser{input: input}
+	for p.pos < len(p.input) {
+		p.step()
+	}
+	p.flush()
+	return Combine(p.out...), p.issues
+}
+
+type legacyParser struct {
+	input  string
+	pos    int
+	text   strings.Builder
+	out    []styledText
+	issues []LegacyIssue
+}
+
+func (p *legacyParser) step() {
+	ch := p.input[p.pos]
+	switch {
+	case ch == '\\' && p.pos+1 < len(p.input) && strings.IndexByte(legacyEscapes, p.input[p.pos+1]) >= 0:
+		p.text.WriteByte(p.input[p.pos+1])
+		p.pos += 2
+		return
+	case strings.HasPrefix(p.input[p.pos:], "```"):
+		if p.pre() {
+			return
+		}
+	case ch == '`':
+		if p.entity("`", InlineFixWidth) {
+			return
+		}
+	case ch == '*':
+		if p.entity("*", BoldText) {
+			return
+		}
+	case ch == '_':
+		if p.entity("_", ItalicText) {
+			return
+		}
+	case ch == '[':
+		if p.link() {
+			return
+		}
+	}
+	p.text.WriteByte(ch)
+	p.pos++
+}
+
+func (p *legacyParser) flush() {
+	if p.text.Len() > 0 {
+		p.out = append(p.out, Text(p.text.String()))
+		p.text.Reset()
+	}
+}
+
+func (p *legacyParser) emit(styled styledText) {
+	p.flush()
+	p.out = append(p.out, styled)
+}
+
+func (p *legacyParser) report(offset int, format string, args ...interface{}) {
+	p.issues = append(p.issues, LegacyIssue{Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *legacyParser) entity(marker string, style func(string) styledText) bool {
+	start := p.pos + len(marker)
+	end := strings.Index(p.input[start:], marker)
+	if end < 0 {
+		p.report(p.pos, "%q is never closed and is kept as text", marker)
+		return false
+	}
+	content := p.input[start : start+end]
+	after := start + end + len(marker)
+	if content == "" {
+		p.report(p.pos, "empty %q entity is kept as text", marker)
+		p.text.WriteString(p.input[p.pos:after])
+		p.pos = after
+		return true
+	}
+
+	switch {
+	case marker == "_" && isWordBefore(p.input, p.pos) && isWordAfter(p.input, after):
+		p.report(p.pos, "underscores inside words are read as italic markers")
+	case marker != "`" && strings.Contains(content, "\n"):
+		p.report(p.pos, "%q entity spans several lines", marker)
+	case marker != "`" && strings.ContainsAny(content, "_*`["):
+		p.report(p.pos, "markup inside a %q entity is literal in legacy Markdown", marker)
+	}
+	p.emit(style(content))
+	p.pos = after
+	return true
+}
+
+func (p *legacyParser) pre() bool {
+	start := p.pos + 3
+	end := strings.Index(p.input[start:], "```")
+	if end < 0 {
+		p.report(p.pos, "\"```\" is never closed and is kept as text")
+		p.text.WriteString("```")
+		p.pos += 3
+		return true
+	}
+	content := p.input[start : start+end]
+	language := ""
+	if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+		if candidate := content[:newline]; !strings.ContainsAny(candidate, " \t") {
+			language = candidate
+			content = content[newline+1:]
+		}
+	}
+	p.emit(fencedCode(language, strings.Split(strings.TrimSuffix(content, "\n"), "\n")))
+	p.pos = start + end + 3
+	return true
+}
+
+func (p *legacyParser) link() bool {
+	close := strings.IndexByte(p.input[p.pos:], ']')
+	if close < 0 {
+		p.report(p.pos, "\"[\" is never closed and is kept as text")
+		return false
+	}
+	close += p.pos
+	if !strings.HasPrefix(p.input[close+1:], "(") {
+		p.report(p.pos, "brackets without a URL are kept as text")
+		p.text.WriteString(p.input[p.pos : close+1])
+		p.pos = close + 1
+		return true
+	}
+	end := strings.IndexByte(p.input[close+2:], ')')
+	if end < 0 {
+		p.report(p.pos, "link URL is never closed and is kept as text")
+		return false
+	}
+	end += close + 2
+	p.emit(InlineURL(p.input[p.pos+1:close], p.input[close+2:end]))
+	p.pos = end + 1
+	return true
+}
+
+func isWordBefore(input string, pos int) bool {
+	r, _ := utf8.DecodeLastRuneInString(input[:pos])
+	return pos > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isWordAfter(input string, pos int) bool {
+	r, _ := utf8.DecodeRuneInString(input[pos:])
+	return pos < len(input) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,70 @@
+package telegrammarkdown_test
+
+import (
+	"reflect"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestFromLegacy(t *testing.T) {
+	got, issues := md.FromLegacy("*Deploy* _v1.2.3_ by [john](tg://user?id=1) in `prod-eu` \\*not bold\\* 5 > 4!")
+	want := "*Deploy* _v1\\.2\\.3_ by [john](tg://user?id=1) in `prod-eu` \\*not bold\\* 5 \\> 4\\!"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+	if len(issues) > 0 {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+}
+
+func TestFromLegacyPre(t *testing.T) {
+	got, _ := md.FromLegacy("```go\nfmt.Println(\"*hi*\")\n```")
+	want := "```go\nfmt.Println(\"*hi*\")\n```"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromLegacyEntitiesDoNotNest(t *testing.T) {
+	got, issues := md.FromLegacy("*bold _not italic_*")
+	want := "*bold \\_not italic\\_*"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+	wantIssues := []md.LegacyIssue{{Offset: 0, Message: `markup inside a "*" entity is literal in legacy Markdown`}}
+	if !reflect.DeepEqual(issues, wantIssues) {
+		t.Errorf("got issues %v, want %v", issues, wantIssues)
+	}
+}
+
+func TestFromLegacyReportsAmbiguities(t *testing.T) {
+	got, issues := md.FromLegacy("file_name_here\n* one\n* two\n[WARN] empty ** and *unclosed")
+	want := "file_name_here\n* one\n* two\n\\[WARN\\] empty \\*\\* and \\*unclosed"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+	wantIssues := []md.LegacyIssue{
+		{Offset: 4, Message: "underscores inside words are read as italic markers"},
+		{Offset: 15, Message: `"*" entity spans several lines`},
+		{Offset: 27, Message: "brackets without a URL are kept as text"},
+		{Offset: 40, Message: `empty "*" entity is kept as text`},
+		{Offset: 47, Message: `"*" is never closed and is kept as text`},
+	}
+	if !reflect.DeepEqual(issues, wantIssues) {
+		t.Errorf("got issues %v, want %v", issues, wantIssues)
+	}
+}
+
+func TestLegacyIssueString(t *testing.T) {
+	got := md.LegacyIssue{Offset: 3, Message: "problem"}.String()
+	want := "offset 3: problem"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
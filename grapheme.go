@@ -0,0 +1,109 @@
+package telegrammarkdown
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = '‍'
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef:
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		return true
+	case r >= 0x1160 && r <= 0x11ff:
+		return true
+	}
+	return r == zeroWidthJoiner
+}
+
+// nextGrapheme returns the length in bytes of the grapheme cluster at the
+// start of input. It covers combining marks, emoji modifier and ZWJ sequences
+// and flags, which is what matters for chat messages.
+func nextGrapheme(input string) int {
+	if input == "" {
+		return 0
+	}
+	first, size := utf8.DecodeRuneInString(input)
+	if first == '\r' && len(input) > 1 && input[1] == '\n' {
+		return 2
+	}
+
+	previous := first
+	for size < len(input) {
+		r, width := utf8.DecodeRuneInString(input[size:])
+		switch {
+		case previous == zeroWidthJoiner:
+		case isGraphemeExtend(r):
+		case isRegionalIndicator(first) && isRegionalIndicator(r) && previous == first && size == utf8.RuneLen(first):
+		default:
+			return size
+		}
+		size += width
+		previous = r
+	}
+	return size
+}
+
+func graphemeCount(input string) int {
+	count := 0
+	for len(input) > 0 {
+		input = input[nextGrapheme(input):]
+		count++
+	}
+	return count
+}
+
+func takeGraphemes(input string, n int) (kept string, cut bool) {
+	end := 0
+	for i := 0; i < n && end < len(input); i++ {
+		end += nextGrapheme(input[end:])
+	}
+	return input[:end], end < len(input)
+}
+
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe30, 0xfe4f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f1e6, 0x1f1ff},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f900, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x3fffd},
+}
+
+func graphemeWidth(grapheme string) int {
+	r, size := utf8.DecodeRuneInString(grapheme)
+	if unicode.IsControl(r) || isGraphemeExtend(r) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	if next, _ := utf8.DecodeRuneInString(grapheme[size:]); next == 0xfe0f {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,477 @@
+package telegrammarkdown
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+type htmlTokenKind int
+
+const (
+	htmlText htmlTokenKind = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+type htmlToken struct {
+	kind        htmlTokenKind
+	data        string
+	attrs       map[string]string
+	selfClosing bool
+}
+
+var (
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+	}
+	rawTextElements = map[string]bool{
+		"script": true, "style": true, "textarea": true, "title": true,
+	}
+	droppedElements = map[string]bool{
+		"head": true, "script": true, "style": true, "template": true, "title": true,
+	}
+	// blockBreaks is the number of line breaks around the content of block
+	// elements that have no MarkdownV2 counterpart.
+	blockBreaks = map[string]int{
+		"address": 2, "article": 2, "aside": 2, "dd": 1, "div": 1, "dl": 1, "dt": 1,
+		"fieldset": 1, "figcaption": 1, "figure": 2, "footer": 2, "form": 1, "h1": 2,
+		"h2": 2, "h3": 2, "h4": 2, "h5": 2, "h6": 2, "header": 2, "hr": 2, "main": 2,
+		"nav": 2, "p": 2, "section": 2, "table": 2, "tr": 1,
+	}
+)
+
+// tokenizeHTML splits an HTML fragment into text, start and end tags. Entities
+// are decoded; comments, doctypes and processing instructions are skipped.
+func tokenizeHTML(input string) []htmlToken {
+	var tokens []htmlToken
+	for pos := 0; pos < len(input); {
+		lt := strings.IndexByte(input[pos:], '<')
+		if lt < 0 {
+			lt = len(input) - pos
+		}
+		if lt > 0 {
+			tokens = append(tokens, htmlToken{kind: htmlText, data: html.UnescapeString(input[pos : pos+lt])})
+			pos += lt
+			continue
+		}
+
+		rest := input[pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			pos += skipPast(rest, "-->", 4)
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			pos += skipPast(rest, ">", 2)
+		case strings.HasPrefix(rest, "</") && len(rest) > 2 && isASCIILetter(rest[2]):
+			name := tagName(rest[2:])
+			tokens = append(tokens, htmlToken{kind: htmlEndTag, data: strings.ToLower(name)})
+			pos += skipPast(rest, ">", 2+len(name))
+		case len(rest) > 1 && isASCIILetter(rest[1]):
+			token, size := startTag(rest)
+			tokens = append(tokens, token)
+			pos += size
+			if rawTextElements[token.data] && !token.selfClosing {
+				end := indexFold(input[pos:], "</"+token.data)
+				if end < 0 {
+					end = len(input) - pos
+				}
+				if end > 0 {
+					tokens = append(tokens, htmlToken{kind: htmlText, data: html.UnescapeString(input[pos : pos+end])})
+				}
+				pos += end
+			}
+		default:
+			tokens = append(tokens, htmlToken{kind: htmlText, data: "<"})
+			pos++
+		}
+	}
+	return tokens
+}
+
+func startTag(input string) (htmlToken, int) {
+	name := tagName(input[1:])
+	token := htmlToken{kind: htmlStartTag, data: strings.ToLower(name), attrs: map[string]string{}}
+	i := 1 + len(name)
+	for i < len(input) {
+		for i < len(input) && isHTMLSpace(input[i]) {
+			i++
+		}
+		switch {
+		case i >= len(input):
+			return token, i
+		case input[i] == '>':
+			return token, i + 1
+		case strings.HasPrefix(input[i:], "/>"):
+			token.selfClosing = true
+			return token, i + 2
+		case input[i] == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(input) && !isHTMLSpace(input[i]) && strings.IndexByte("=>/", input[i]) < 0 {
+			i++
+		}
+		key := strings.ToLower(input[start:i])
+		for i < len(input) && isHTMLSpace(input[i]) {
+			i++
+		}
+		value := ""
+		if i < len(input) && input[i] == '=' {
+			i++
+			for i < len(input) && isHTMLSpace(input[i]) {
+				i++
+			}
+			value, i = attributeValue(input, i)
+		}
+		if _, ok := token.attrs[key]; !ok {
+			token.attrs[key] = html.UnescapeString(value)
+		}
+	}
+	return token, len(input)
+}
+
+func attributeValue(input string, i int) (string, int) {
+	if i < len(input) && (input[i] == '"' || input[i] == '\'') {
+		end := strings.IndexByte(input[i+1:], input[i])
+		if end < 0 {
+			return input[i+1:], len(input)
+		}
+		return input[i+1 : i+1+end], i + end + 2
+	}
+	start := i
+	for i < len(input) && !isHTMLSpace(input[i]) && input[i] != '>' {
+		i++
+	}
+	return input[start:i], i
+}
+
+func tagName(input string) string {
+	i := 0
+	for i < len(input) && (isASCIILetter(input[i]) || input[i] >= '0' && input[i] <= '9' || input[i] == '-' || input[i] == ':') {
+		i++
+	}
+	return input[:i]
+}
+
+func skipPast(input, delimiter string, from int) int {
+	if end := strings.Index(input[from:], delimiter); end >= 0 {
+		return from + end + len(delimiter)
+	}
+	return len(input)
+}
+
+// indexFold returns the index of the lower case ASCII substr in input,
+// ignoring the case of ASCII letters in input.
+func indexFold(input, substr string) int {
+	for i := 0; i+len(substr) <= len(input); i++ {
+		j := 0
+		for j < len(substr) && toLowerASCII(input[i+j]) == substr[j] {
+			j++
+		}
+		if j == len(substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func toLowerASCII(ch byte) byte {
+	if 'A' <= ch && ch <= 'Z' {
+		return ch + 'a' - 'A'
+	}
+	return ch
+}
+
+type htmlNode struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*htmlNode
+}
+
+// parseHTML builds a tree from the tokens, closing unclosed elements the way
+// browsers do for the common cases: a new paragraph or block closes an open
+// paragraph, and a new list item closes the previous one.
+func parseHTML(tokens []htmlToken) *htmlNode {
+	root := &htmlNode{}
+	stack := []*htmlNode{root}
+	closeTo := func(i int) {
+		stack = stack[:i]
+	}
+	open := func(tag string, stopAt ...string) int {
+		for i := len(stack) - 1; i > 0; i-- {
+			if stack[i].tag == tag {
+				return i
+			}
+			for _, stop := range stopAt {
+				if stack[i].tag == stop {
+					return -1
+				}
+			}
+		}
+		return -1
+	}
+
+	for _, token := range tokens {
+		top := stack[len(stack)-1]
+		switch token.kind {
+		case htmlText:
+			top.children = append(top.children, &htmlNode{text: token.data})
+		case htmlStartTag:
+			if token.data == "li" {
+				if i := open("li", "ul", "ol"); i > 0 {
+					closeTo(i)
+				}
+			} else if blockBreaks[token.data] > 0 || isStructural(token.data) {
+				if i := open("p", "div", "li", "blockquote"); i > 0 {
+					closeTo(i)
+				}
+			}
+			top = stack[len(stack)-1]
+			element := &htmlNode{tag: token.data, attrs: token.attrs}
+			top.children = append(top.children, element)
+			if !voidElements[token.data] && !token.selfClosing {
+				stack = append(stack, element)
+			}
+		case htmlEndTag:
+			if i := open(token.data); i > 0 {
+				closeTo(i)
+			}
+		}
+	}
+	return root
+}
+
+func isStructural(tag string) bool {
+	switch tag {
+	case "blockquote", "li", "ol", "pre", "ul":
+		return true
+	}
+	return false
+}
+
+func FromHTML(input string) styledText {
+	c := htmlConverter{start: true}
+	c.children(parseHTML(tokenizeHTML(normalizeLineEndings(input))))
+	return Combine(c.out...)
+}
+
+// htmlConverter collapses whitespace and line breaks lazily: spaces and
+// breaks are only written once more text follows them, so that they never
+// end up at the edges of a styled entity or of the output.
+type htmlConverter struct {
+	out          []styledText
+	start        bool
+	spaced       bool
+	pendingSpace bool
+	breaks       int
+}
+
+func (c *htmlConverter) children(n *htmlNode) {
+	for _, child := range n.children {
+		c.node(child)
+	}
+}
+
+func (c *htmlConverter) node(n *htmlNode) {
+	switch n.tag {
+	case "":
+		c.text(n.text)
+	case "b", "strong":
+		c.wrap(n, merged(boldNode))
+	case "i", "em":
+		c.wrap(n, merged(italicNode))
+	case "u", "ins":
+		c.wrap(n, merged(underlineNode))
+	case "s", "del", "strike":
+		c.wrap(n, merged(strikethroughNode))
+	case "tg-spoiler":
+		c.wrap(n, merged(spoilerNode))
+	case "span":
+		if hasClass(n, "tg-spoiler") {
+			c.wrap(n, merged(spoilerNode))
+		} else {
+			c.children(n)
+		}
+	case "a":
+		if href := strings.TrimSpace(n.attrs["href"]); href != "" {
+			c.wrap(n, func(input ...styledText) styledText {
+				return Link(href, input...)
+			})
+		} else {
+			c.children(n)
+		}
+	case "code":
+		if code := textContent(n); code != "" {
+			c.flush()
+			c.emit(InlineFixWidth(code))
+		}
+	case "br":
+		if !c.start {
+			c.breaks++
+		}
+	case "pre":
+		c.emitBlock(c.pre(n), 2)
+	case "blockquote":
+		quote := htmlConverter{start: true}
+		quote.children(n)
+		if len(quote.out) > 0 {
+			c.emitBlock(Blockquote(quote.out...), 2)
+		}
+	case "ul", "ol":
+		if list := c.list(n); len(list.items) > 0 {
+			c.emitBlock(list.Styled(), 1)
+		}
+	default:
+		if droppedElements[n.tag] {
+			return
+		}
+		breaks := blockBreaks[n.tag]
+		c.block(breaks)
+		c.children(n)
+		c.block(breaks)
+	}
+}
+
+func (c *htmlConverter) text(text string) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r < 0x80 && isHTMLSpace(byte(r))
+	})
+	if text != "" && isHTMLSpace(text[0]) {
+		c.pendingSpace = true
+	}
+	if len(words) == 0 {
+		return
+	}
+	c.flush()
+	c.emit(Text(strings.Join(words, " ")))
+	c.pendingSpace = isHTMLSpace(text[len(text)-1])
+}
+
+// wrap styles the content of an inline element. Pending and leading
+// whitespace is written before the element, unless the element is empty.
+func (c *htmlConverter) wrap(n *htmlNode, style func(...styledText) styledText) {
+	text := textContent(n)
+	if strings.TrimSpace(text) == "" {
+		c.children(n)
+		return
+	}
+	if isHTMLSpace(text[0]) {
+		c.pendingSpace = true
+	}
+	c.flush()
+	outer := c.out
+	c.out = nil
+	c.children(n)
+	inner := c.out
+	c.out = outer
+	c.out = append(c.out, style(inner...))
+}
+
+// merged returns a style for wrap that merges nested elements of the same
+// kind, such as <b> inside <strong>, into the outer one.
+func merged(kind nodeKind) func(...styledText) styledText {
+	return func(input ...styledText) styledText {
+		return enclose(kind, input...)
+	}
+}
+
+func (c *htmlConverter) pre(n *htmlNode) styledText {
+	language := ""
+	for _, child := range n.children {
+		if child.tag != "code" {
+			continue
+		}
+		for _, class := range strings.Fields(child.attrs["class"]) {
+			if strings.HasPrefix(class, "language-") {
+				language = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+	code := strings.TrimPrefix(textContent(n), "\n")
+	return fencedCode(language, strings.Split(strings.TrimSuffix(code, "\n"), "\n"))
+}
+
+func (c *htmlConverter) list(n *htmlNode) *List {
+	list := &List{Ordered: n.tag == "ol"}
+	if start, err := strconv.Atoi(n.attrs["start"]); err == nil && list.Ordered {
+		list.Start = &start
+	}
+	for _, child := range n.children {
+		if child.tag != "li" {
+			continue
+		}
+		item := htmlConverter{start: true}
+		item.children(child)
+		list.Add(Combine(item.out...))
+	}
+	return list
+}
+
+func (c *htmlConverter) block(breaks int) {
+	if !c.start && breaks > c.breaks {
+		c.breaks = breaks
+	}
+}
+
+// flush writes the pending line breaks, or the pending space if the current
+// line has content.
+func (c *htmlConverter) flush() {
+	switch {
+	case c.breaks > 0:
+		c.out = append(c.out, styledText{text: strings.Repeat(newLine, c.breaks), escaped: true})
+		c.spaced = true
+	case c.pendingSpace && !c.start && !c.spaced:
+		c.out = append(c.out, Space())
+		c.spaced = true
+	}
+	c.breaks = 0
+	c.pendingSpace = false
+}
+
+func (c *htmlConverter) emitBlock(styled styledText, breaks int) {
+	c.block(breaks)
+	c.flush()
+	c.emit(styled)
+	c.block(breaks)
+}
+
+func (c *htmlConverter) emit(styled styledText) {
+	c.out = append(c.out, styled)
+	c.start = false
+	c.spaced = false
+}
+
+func textContent(n *htmlNode) string {
+	if n.tag == "" {
+		return n.text
+	}
+	if n.tag == "br" {
+		return "\n"
+	}
+	var text strings.Builder
+	for _, child := range n.children {
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
+
+func hasClass(n *htmlNode, class string) bool {
+	for _, c := range strings.Fields(n.attrs["class"]) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTMLSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\f' || ch == '\r'
+}
+
+func isASCIILetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
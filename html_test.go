@@ -0,0 +1,129 @@
+package telegrammarkdown_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestFromHTMLStyles(t *testing.T) {
+	input := `<b>b</b> <strong>strong <i>nested</i></strong> <em>em</em> <u>u</u> <ins>ins</ins> ` +
+		`<s>s</s> <del>del</del> <strike>strike</strike> <span class="x tg-spoiler">spoiler</span> <tg-spoiler>tg</tg-spoiler>`
+
+	got := md.FromHTML(input)
+	want := "*b* *strong _nested_* _em_ __u__ __ins__ ~s~ ~del~ ~strike~ ||spoiler|| ||tg||"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLNestedStyles(t *testing.T) {
+	got := md.FromHTML("<b>a <b>b</b> c</b> <i>x<em>y</em></i> <u>u <ins>ins</ins> <i>i</i></u>")
+	want := "*a b c* _xy_ __u ins _i_\r__"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLUnderlineInItalic(t *testing.T) {
+	got := md.FromHTML("<b><i>a <u>b</u></i></b> <i><u>c</u> d</i>")
+	want := "*_a __b___* _\r__c__ d_"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLLinksAndCode(t *testing.T) {
+	input := `See <a href="https://example.com/?a=1&amp;b=(2)">the <b>docs</b></a> and <code>a_b &lt;c&gt;</code>.`
+
+	got := md.FromHTML(input)
+	want := "See [the *docs*](https://example.com/?a=1&b=(2\\)) and `a_b <c>`\\."
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLWhitespace(t *testing.T) {
+	input := "\n  <p>  lots   of\n\twhitespace <b> inside </b>and&nbsp;nbsp </p>  \n"
+
+	got := md.FromHTML(input)
+	want := "lots of whitespace *inside* and nbsp"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLLineBreaks(t *testing.T) {
+	got := md.FromHTML("<p>first<br>line</p><p>second</p><div>div</div><div>another</div>")
+	want := "first\nline\n\nsecond\n\ndiv\nanother"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLPre(t *testing.T) {
+	got := md.FromHTML("<pre><code class=\"language-go\">\nif a < b {\n\treturn `x`\n}\n</code></pre>")
+	want := "```go\nif a < b {\n\treturn \\`x\\`\n}\n```"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLBlockquote(t *testing.T) {
+	got := md.FromHTML("before<blockquote>quoted <b>text</b><p>second</p></blockquote>after")
+	want := "before\n\n>quoted *text*\n>\n>second\n\nafter"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLLists(t *testing.T) {
+	got := md.FromHTML(`<ul><li>one<li>two<ul><li>nested</ul></li></ul><ol start="3"><li>three</li><li>four</li></ol>`)
+	want := "• one\n• two\n  • nested\n3\\. three\n4\\. four"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLRawTextWithNonASCII(t *testing.T) {
+	input := "<textarea>" + strings.Repeat("\xff", 10) + "İ</TEXTAREA> after <title>x\xffİ</title>"
+
+	got := md.FromHTML(input)
+	want := strings.Repeat("\xff", 10) + "İ after"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLDropsUnsupportedTags(t *testing.T) {
+	input := `<!DOCTYPE html><html><head><title>t</title><style>b { }</style></head>` +
+		`<body><!-- note --><custom data-x='1'>kept</custom> <img src="x.png"> 5 < 6` +
+		`<script>alert("<b>x</b>")</script></body></html>`
+
+	got := md.FromHTML(input)
+	want := "kept 5 < 6"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestFromHTMLListStartingAtZero(t *testing.T) {
+	got := md.FromHTML(`<ol start="0"><li>zero</li><li>one</li></ol>`)
+	want := "0\\. zero\n1\\. one"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
@@ -0,0 +1,196 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type keyValuePair struct {
+	key     string
+	value   styledText
+	section *KeyValue
+}
+
+type KeyValue struct {
+	CodeBlock bool
+	Separator string
+	Indent    int
+	pairs     []keyValuePair
+}
+
+func (kv *KeyValue) Add(key string, value styledText) {
+	kv.pairs = append(kv.pairs, keyValuePair{key: key, value: value})
+}
+
+func (kv *KeyValue) AddText(key, value string) {
+	kv.Add(key, Text(value))
+}
+
+func (kv *KeyValue) AddSection(key string, section *KeyValue) {
+	kv.pairs = append(kv.pairs, keyValuePair{key: key, section: section})
+}
+
+func KeyValueFromMap(m interface{}) *KeyValue {
+	kv := &KeyValue{}
+	v := reflect.Indirect(reflect.ValueOf(m))
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return kv
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	for _, key := range keys {
+		kv.addValue(key.String(), v.MapIndex(key))
+	}
+	return kv
+}
+
+func KeyValueFromStruct(s interface{}) *KeyValue {
+	kv := &KeyValue{}
+	v := reflect.Indirect(reflect.ValueOf(s))
+	if v.Kind() != reflect.Struct {
+		return kv
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := field.Name
+		omitEmpty := false
+		if tag, ok := field.Tag.Lookup("md"); ok {
+			options := strings.Split(tag, ",")
+			if options[0] == "-" {
+				continue
+			}
+			if options[0] != "" {
+				key = options[0]
+			}
+			for _, option := range options[1:] {
+				omitEmpty = omitEmpty || option == "omitempty"
+			}
+		}
+		value := v.Field(i)
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+		kv.addValue(key, value)
+	}
+	return kv
+}
+
+func (kv *KeyValue) addValue(key string, value reflect.Value) {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			kv.AddText(key, "")
+			return
+		}
+		value = value.Elem()
+	}
+
+	if value.CanInterface() {
+		switch v := value.Interface().(type) {
+		case styledText:
+			kv.Add(key, v)
+			return
+		case fmt.Stringer:
+			kv.AddText(key, v.String())
+			return
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		kv.AddSection(key, KeyValueFromStruct(value.Interface()))
+	case reflect.Map:
+		kv.AddSection(key, KeyValueFromMap(value.Interface()))
+	default:
+		kv.AddText(key, fmt.Sprint(value.Interface()))
+	}
+}
+
+func (kv *KeyValue) String() string {
+	return kv.Styled().String()
+}
+
+func (kv *KeyValue) Styled() styledText {
+	if kv.CodeBlock {
+		lines := strings.Join(kv.codeLines(""), "\n")
+		return styledText{
+			text:    encloseText(escape(lines, codeEscapes), "```\n", "\n```"),
+			escaped: true,
+		}
+	}
+	return CombineWithNewLine(kv.styledLines("")...)
+}
+
+func (kv *KeyValue) separator() string {
+	if kv.Separator == "" {
+		return ":"
+	}
+	return kv.Separator
+}
+
+func (kv *KeyValue) indent() string {
+	if kv.Indent <= 0 {
+		return "  "
+	}
+	return strings.Repeat(" ", kv.Indent)
+}
+
+func (kv *KeyValue) inherit(section *KeyValue) *KeyValue {
+	inherited := *section
+	inherited.Separator = kv.Separator
+	inherited.Indent = kv.Indent
+	return &inherited
+}
+
+func (kv *KeyValue) codeLines(prefix string) []string {
+	width := 0
+	for _, pair := range kv.pairs {
+		if pair.section != nil {
+			continue
+		}
+		if w := utf8.RuneCountInString(pair.key); w > width {
+			width = w
+		}
+	}
+
+	var lines []string
+	for _, pair := range kv.pairs {
+		label := pair.key + kv.separator()
+		if pair.section != nil {
+			lines = append(lines, prefix+label)
+			lines = append(lines, kv.inherit(pair.section).codeLines(prefix+kv.indent())...)
+			continue
+		}
+		label = alignText(label, width+utf8.RuneCountInString(kv.separator()), Left)
+		lines = append(lines, prefix+label+" "+Plain(pair.value))
+	}
+	return lines
+}
+
+func (kv *KeyValue) styledLines(prefix string) []styledText {
+	var lines []styledText
+	for _, pair := range kv.pairs {
+		label := Combine(
+			styledText{text: prefix, escaped: true},
+			BoldText(pair.key),
+			Text(kv.separator()),
+		)
+		if pair.section != nil {
+			lines = append(lines, label)
+			lines = append(lines, kv.inherit(pair.section).styledLines(prefix+kv.indent())...)
+			continue
+		}
+		lines = append(lines, CombineWithSpace(label, pair.value))
+	}
+	return lines
+}
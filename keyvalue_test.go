@@ -0,0 +1,82 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestKeyValue(t *testing.T) {
+	kv := md.KeyValue{}
+	kv.AddText("status", "ok")
+	kv.Add("version", md.ItalicText("1.2.3"))
+
+	got := kv.String()
+	want := "*status*: ok\n*version*: _1\\.2\\.3_"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestKeyValueCodeBlock(t *testing.T) {
+	kv := md.KeyValue{CodeBlock: true}
+	kv.AddText("status", "ok")
+	kv.Add("version", md.BoldText("1.2.3"))
+	section := &md.KeyValue{}
+	section.AddText("host", "db-1")
+	section.AddText("port", "5432")
+	kv.AddSection("database", section)
+
+	got := kv.String()
+	want := "```\n"
+	want += "status:  ok\n"
+	want += "version: 1.2.3\n"
+	want += "database:\n"
+	want += "  host: db-1\n"
+	want += "  port: 5432\n"
+	want += "```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestKeyValueFromMap(t *testing.T) {
+	kv := md.KeyValueFromMap(map[string]interface{}{
+		"b": 2,
+		"a": "x",
+		"c": map[string]int{"d": 4},
+	})
+	kv.CodeBlock = true
+
+	got := kv.String()
+	want := "```\n"
+	want += "a: x\n"
+	want += "b: 2\n"
+	want += "c:\n"
+	want += "  d: 4\n"
+	want += "```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestKeyValueFromStruct(t *testing.T) {
+	type deploy struct {
+		Service string `md:"Service name"`
+		Replica int
+		Comment string `md:",omitempty"`
+		Secret  string `md:"-"`
+		hidden  string
+	}
+	kv := md.KeyValueFromStruct(deploy{Service: "api", Replica: 3, Secret: "s", hidden: "h"})
+
+	got := kv.String()
+	want := "*Service name*: api\n*Replica*: 3"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
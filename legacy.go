@@ -0,0 +1,123 @@
+package telegrammarkdown
+
+import "strings"
+
+// legacyEscapes are the characters that have to be escaped outside of an
+// entity in legacy Markdown. Inside an entity nothing can be escaped.
+const legacyEscapes = "_*`["
+
+var legacyEscaper = newEscaper(legacyEscapes)
+
+// Legacy renders input for parse_mode=Markdown. Legacy Markdown only knows
+// bold, italic, links, code and pre and cannot nest them, so underline,
+// strikethrough, spoilers and quotes are reduced to their text, nested styles
+// keep the outermost bold or italic, and links or code inside a style close
+// it around them.
+func Legacy(input styledText) string {
+	input.escape()
+	return LegacyText(input.text)
+}
+
+func LegacyText(input string) string {
+	w := legacyWriter{}
+	w.nodes(parseNodes(input), 0)
+	w.close()
+	return w.out.String()
+}
+
+type legacyWriter struct {
+	out  strings.Builder
+	open byte
+}
+
+func (w *legacyWriter) nodes(nodes []*node, style byte) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			w.text(n.text, style)
+		case quoteNode:
+			w.text(">", 0)
+		case codeNode:
+			w.close()
+			w.code("`", n.text, "`")
+		case preNode:
+			w.close()
+			w.code("```"+n.language+"\n", n.text, "```")
+		case linkNode:
+			w.close()
+			w.link(n)
+		case boldNode, italicNode:
+			if style == 0 {
+				w.nodes(n.children, nodeMarkers[n.kind][0])
+			} else {
+				w.nodes(n.children, style)
+			}
+		default:
+			w.nodes(n.children, style)
+		}
+	}
+}
+
+// text writes text in the given style. As escapes are not allowed inside an
+// entity, the entity is closed around every occurrence of its own marker.
+func (w *legacyWriter) text(text string, style byte) {
+	if style == 0 {
+		w.close()
+		legacyEscaper.write(&w.out, text)
+		return
+	}
+	for text != "" {
+		i := strings.IndexByte(text, style)
+		if i < 0 {
+			i = len(text)
+		}
+		if i > 0 {
+			if w.open != style {
+				w.close()
+				w.out.WriteByte(style)
+				w.open = style
+			}
+			w.out.WriteString(text[:i])
+		}
+		if i < len(text) {
+			w.close()
+			w.out.WriteString(`\` + string(style))
+			i++
+		}
+		text = text[i:]
+	}
+}
+
+func (w *legacyWriter) close() {
+	if w.open != 0 {
+		w.out.WriteByte(w.open)
+		w.open = 0
+	}
+}
+
+// code writes a code or pre entity, splitting it around the backticks that
+// would end it early.
+func (w *legacyWriter) code(open, text, end string) {
+	parts := strings.Split(text, end)
+	for i, part := range parts {
+		if i > 0 {
+			legacyEscaper.write(&w.out, end)
+		}
+		if part != "" {
+			w.out.WriteString(open + part + end)
+		}
+	}
+}
+
+// link writes a link with its text unstyled. A closing bracket cannot appear
+// in the text, so such links are written as the text followed by the URL.
+func (w *legacyWriter) link(n *node) {
+	var text strings.Builder
+	PlainOptions{}.writeNodes(&text, n.children)
+	url := strings.Replace(n.url, ")", "%29", -1)
+	if strings.Contains(text.String(), "]") {
+		legacyEscaper.write(&w.out, text.String()+" ("+n.url+")")
+		return
+	}
+	w.out.WriteString("[" + text.String() + "](" + url + ")")
+}
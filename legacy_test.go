@@ -0,0 +1,70 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestLegacy(t *testing.T) {
+	input := md.CombineWithSpace(
+		md.BoldText("bold"),
+		md.ItalicText("italic"),
+		md.Text("snake_case *star* [x] 1.5!"),
+		md.InlineFixWidth("a_b"),
+		md.InlineURL("docs", "https://example.com/a_(b)"),
+	)
+
+	got := md.Legacy(input)
+	want := "*bold* _italic_ snake\\_case \\*star\\* \\[x] 1.5! `a_b` [docs](https://example.com/a_(b%29)"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestLegacyDegradesUnsupportedStyles(t *testing.T) {
+	input := md.CombineWithSpace(
+		md.UnderlineText("underline"),
+		md.StrikethroughText("strike"),
+		md.SpoilerText("spoiler"),
+		md.Bold(md.Text("bold "), md.ItalicText("nested")),
+		md.Blockquote(md.Text("quote")),
+	)
+
+	got := md.Legacy(input)
+	want := "underline strike spoiler *bold nested* >quote"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestLegacyEntityContainingItsMarker(t *testing.T) {
+	got := md.Legacy(md.Combine(md.BoldText("2*2=4"), md.Text(" "), md.ItalicText("snake_case")))
+	want := "*2*\\**2=4* _snake_\\__case_"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestLegacyLinkAndCodeInsideStyle(t *testing.T) {
+	input := md.Bold(md.Text("see "), md.InlineURL("docs", "https://example.com"), md.Text(" and "), md.InlineFixWidth("a`b"))
+
+	got := md.Legacy(input)
+	want := "*see *[docs](https://example.com)* and *`a`\\``b`"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestLegacyText(t *testing.T) {
+	got := md.LegacyText("```go\nfmt.Println(\"hi\")\n```\n[a \\[b\\]](https://x.io)")
+	want := "```go\nfmt.Println(\"hi\")\n```\na \\[b] (https://x.io)"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
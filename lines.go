@@ -0,0 +1,80 @@
+package telegrammarkdown
+
+import "strings"
+
+var lineEndings = strings.NewReplacer("\r\n", newLine, "\r", newLine)
+
+func normalizeLineEndings(input string) string {
+	if strings.IndexByte(input, '\r') < 0 {
+		return input
+	}
+	return lineEndings.Replace(input)
+}
+
+// normalizeMarkupLineEndings is normalizeLineEndings for escaped text. It
+// keeps the '\r' that separates an italic marker from a following '_'.
+func normalizeMarkupLineEndings(input string) string {
+	if strings.IndexByte(input, '\r') < 0 {
+		return input
+	}
+	var out strings.Builder
+	afterItalic := -1
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		switch {
+		case ch == '\\' && i+1 < len(input):
+			out.WriteString(input[i : i+2])
+			i++
+		case ch == '_':
+			out.WriteByte(ch)
+			afterItalic = i + 1
+		case ch == '\r' && i == afterItalic && strings.HasPrefix(input[i+1:], "_"):
+			out.WriteByte(ch)
+		case ch == '\r' && strings.HasPrefix(input[i+1:], "\n"):
+		case ch == '\r':
+			out.WriteString(newLine)
+		default:
+			out.WriteByte(ch)
+		}
+	}
+	return out.String()
+}
+
+type LineOptions struct {
+	KeepCarriageReturns bool
+	TrimTrailingSpace   bool
+	CollapseBlankLines  bool
+	MaxBlankLines       int
+}
+
+func NormalizeLines(input styledText, options LineOptions) styledText {
+	input.escape()
+	text := input.text
+	if !options.KeepCarriageReturns {
+		text = normalizeMarkupLineEndings(text)
+	}
+
+	lines := strings.Split(text, newLine)
+	normalized := make([]string, 0, len(lines))
+	blank := 0
+	for _, line := range lines {
+		if options.TrimTrailingSpace {
+			line = strings.TrimRight(line, " \t")
+		}
+		if strings.TrimSpace(line) == "" {
+			blank++
+			if options.CollapseBlankLines && blank > options.MaxBlankLines {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		normalized = append(normalized, line)
+	}
+
+	return styledText{text: strings.Join(normalized, newLine), escaped: true}
+}
+
+func CollapseBlankLines(input styledText) styledText {
+	return NormalizeLines(input, LineOptions{CollapseBlankLines: true, MaxBlankLines: 1})
+}
@@ -0,0 +1,80 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestTextNewLine(t *testing.T) {
+	got := md.Text("first\nsecond\n")
+	want := "first\nsecond\n"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	input := md.Text("first\r\nsecond\rthird\n")
+
+	got := md.NormalizeLines(input, md.LineOptions{})
+	want := "first\nsecond\nthird\n"
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+
+	got = md.NormalizeLines(input, md.LineOptions{KeepCarriageReturns: true})
+	want = "first\r\nsecond\rthird\n"
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestNormalizeLinesKeepsItalicSeparator(t *testing.T) {
+	input := md.Combine(md.HeadingText(4, "x"), md.Text("\r\n\r\n\r\n_y\r"), md.ItalicText("z"))
+
+	got := md.NormalizeLines(input, md.LineOptions{})
+	want := "___x_\r__\n\n\n\\_y\n_z_"
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+
+	got = md.CollapseBlankLines(input)
+	want = "___x_\r__\n\n\\_y\n_z_"
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestNormalizeLines(t *testing.T) {
+	input := md.Combine(md.Text("a  \n\n\n\nb"), md.NewLine(), md.BoldText("c"))
+	got := md.NormalizeLines(input, md.LineOptions{
+		TrimTrailingSpace:  true,
+		CollapseBlankLines: true,
+		MaxBlankLines:      2,
+	})
+	want := "a\n\n\nb\n*c*"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestCollapseBlankLines(t *testing.T) {
+	got := md.CollapseBlankLines(md.Text("a\n\n \n\nb"))
+	want := "a\n\nb"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestHashtagWithNewLine(t *testing.T) {
+	got := md.Hashtag("several\nwords")
+	want := `\#several\_words`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
@@ -0,0 +1,109 @@
+package telegrammarkdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const defaultBullet = "•"
+
+type listItem struct {
+	text    styledText
+	sublist *List
+}
+
+type List struct {
+	Ordered bool
+	Bullet  string
+	// Start is the number of the first item of an ordered list, 1 if nil.
+	Start  *int
+	Indent int
+	items  []listItem
+}
+
+func BulletList(items ...styledText) styledText {
+	list := List{}
+	list.Add(items...)
+	return list.Styled()
+}
+
+func OrderedList(items ...styledText) styledText {
+	list := List{Ordered: true}
+	list.Add(items...)
+	return list.Styled()
+}
+
+func (l *List) Add(items ...styledText) {
+	for _, item := range items {
+		item.escape()
+		l.items = append(l.items, listItem{text: item})
+	}
+}
+
+func (l *List) AddText(items ...string) {
+	for _, item := range items {
+		l.Add(Text(item))
+	}
+}
+
+func (l *List) AddList(sublist *List) {
+	if len(l.items) == 0 {
+		l.items = append(l.items, listItem{text: Text("")})
+	}
+	last := &l.items[len(l.items)-1]
+	if last.sublist == nil {
+		last.sublist = sublist
+		return
+	}
+	last.sublist.items = append(last.sublist.items, sublist.items...)
+}
+
+func (l *List) String() string {
+	return l.Styled().String()
+}
+
+func (l *List) Styled() styledText {
+	return styledText{
+		text:    strings.Join(l.lines(""), newLine),
+		escaped: true,
+	}
+}
+
+func (l *List) marker(i int) string {
+	if !l.Ordered {
+		if l.Bullet == "" {
+			return defaultBullet
+		}
+		return l.Bullet
+	}
+	start := 1
+	if l.Start != nil {
+		start = *l.Start
+	}
+	width := len(strconv.Itoa(start + len(l.items) - 1))
+	return alignText(strconv.Itoa(start+i)+".", width+1, Right)
+}
+
+func (l *List) lines(prefix string) []string {
+	var lines []string
+	for i, item := range l.items {
+		marker := l.marker(i)
+		hanging := strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+		for j, line := range strings.Split(item.text.text, newLine) {
+			if j == 0 {
+				lines = append(lines, prefix+escape(marker, escapes)+" "+line)
+				continue
+			}
+			lines = append(lines, prefix+hanging+line)
+		}
+		if item.sublist != nil {
+			indent := hanging
+			if l.Indent > 0 {
+				indent = strings.Repeat(" ", l.Indent)
+			}
+			lines = append(lines, item.sublist.lines(prefix+indent)...)
+		}
+	}
+	return lines
+}
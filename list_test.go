@@ -0,0 +1,92 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestBulletList(t *testing.T) {
+	got := md.BulletList(md.Text("first"), md.BoldText("second"))
+	want := "• first\n• *second*"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestOrderedList(t *testing.T) {
+	got := md.OrderedList(md.Text("first"), md.Text("second"))
+	want := "1\\. first\n2\\. second"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestOrderedListStartAlignsNumbers(t *testing.T) {
+	start := 9
+	list := md.List{Ordered: true, Start: &start}
+	list.AddText("nine", "ten")
+
+	got := list.String()
+	want := " 9\\. nine\n10\\. ten"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestListCustomBullet(t *testing.T) {
+	list := md.List{Bullet: "-"}
+	list.AddText("item")
+
+	got := list.String()
+	want := `\- item`
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestNestedList(t *testing.T) {
+	sublist := &md.List{Ordered: true}
+	sublist.AddText("a", "b")
+
+	list := md.List{}
+	list.AddText("parent")
+	list.AddList(sublist)
+	list.AddText("sibling")
+
+	got := list.String()
+	want := "• parent\n  1\\. a\n  2\\. b\n• sibling"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestListHangingIndent(t *testing.T) {
+	list := md.List{Ordered: true}
+	list.Add(md.CombineWithNewLine(md.Text("first line"), md.Text("wrapped")))
+
+	got := list.String()
+	want := "1\\. first line\n   wrapped"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestOrderedListStartAtZero(t *testing.T) {
+	start := 0
+	list := md.List{Ordered: true, Start: &start}
+	list.AddText("zero", "one")
+
+	got := list.String()
+	want := "0\\. zero\n1\\. one"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
@@ -3,10 +3,15 @@
 package telegrammarkdown
 
 import (
-	"fmt"
+	"io"
+	"strings"
 )
 
-const escapes = `_*[]()~>#+-=|{}.!'` + "`"
+const (
+	escapes     = `_*[]()~>#+-=|{}.!'` + "`"
+	codeEscapes = "`\\"
+	urlEscapes  = `)\`
+)
 
 type styledText struct {
 	text    string
@@ -21,6 +26,12 @@ func (s styledText) String() string {
 	return s.text
 }
 
+func (s styledText) WriteTo(w io.Writer) (int64, error) {
+	s.escape()
+	n, err := io.WriteString(w, s.text)
+	return int64(n), err
+}
+
 func (s *styledText) escape() {
 	if s.escaped {
 		return
@@ -46,7 +57,7 @@ func Space() styledText {
 
 func NewLine() styledText {
 	styled := styledText{
-		text:    `\\n`,
+		text:    newLine,
 		escaped: true,
 	}
 	return styled
@@ -93,21 +104,38 @@ func SpoilerText(input string) styledText {
 }
 
 func InlineURL(text, url string) styledText {
-	styled := styledText{text: fmt.Sprintf("[%s](%s)",
-		escape(text, escapes),
-		escape(url, `)\`),
-	),
+	styled := styledText{
+		text:    "[" + escape(text, escapes) + "](" + escape(url, urlEscapes) + ")",
 		escaped: true,
 	}
 	return styled
 }
 
+func Link(url string, input ...styledText) styledText {
+	return styledText{
+		text:    "[" + getCombinedText(input...) + "](" + escape(url, urlEscapes) + ")",
+		escaped: true,
+	}
+}
+
+// Blockquote prefixes every line with '>'. Telegram does not nest quotes, so
+// lines that are already quoted are kept as they are.
+func Blockquote(input ...styledText) styledText {
+	lines := strings.Split(getCombinedText(input...), newLine)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ">") {
+			lines[i] = ">" + line
+		}
+	}
+	return styledText{text: strings.Join(lines, newLine), escaped: true}
+}
+
 func MentionUser(username string) styledText {
-	return styledText{text: fmt.Sprintf("@%s", username), escaped: true}
+	return styledText{text: "@" + username, escaped: true}
 }
 
 func InlineMentionUser(text, userId string) styledText {
-	return InlineURL(text, fmt.Sprintf("tg://user?id=%s", userId))
+	return InlineURL(text, "tg://user?id="+userId)
 }
 
 func Hashtag(input string) styledText {
@@ -115,7 +143,7 @@ func Hashtag(input string) styledText {
 }
 
 func InlineFixWidth(input string) styledText {
-	return styledText{text: encloseText(input, "`", "`"), escaped: true}
+	return styledText{text: encloseText(escape(input, codeEscapes), "`", "`"), escaped: true}
 }
 
 func Preformatted(input string) styledText {
@@ -124,7 +152,7 @@ func Preformatted(input string) styledText {
 
 func CodeBlock(language, input string) styledText {
 	return styledText{
-		text:    encloseText(input, "```"+language+"\n", "```\n"),
+		text:    encloseText(escape(input, codeEscapes), "```"+language+"\n", "```\n"),
 		escaped: true,
 	}
 }
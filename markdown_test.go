@@ -213,6 +213,24 @@ func TestSimpleInlineFixWidth(t *testing.T) {
 	}
 }
 
+func TestInlineFixWidthEscapesCode(t *testing.T) {
+	got := md.InlineFixWidth("a`b\\c*d")
+	want := "`a\\`b\\\\c*d`"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestCodeBlockEscapesCode(t *testing.T) {
+	got := md.CodeBlock("go", "s := `a\\b`\n")
+	want := "```go\ns := \\`a\\\\b\\`\n```\n"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
 func TestSimpleMentionUser(t *testing.T) {
 	got := md.MentionUser("an_awsome_user")
 	want := `@an_awsome_user`
@@ -290,7 +308,7 @@ func TestCombineWithNewLine(t *testing.T) {
 		md.InlineURL("link", "golang.org"),
 		md.BoldText("bold"),
 	)
-	want := `\#serveral\_words\\n[link](golang.org)\\n*bold*`
+	want := "\\#serveral\\_words\n[link](golang.org)\n*bold*"
 
 	if !got.Equals(want) {
 		t.Errorf(errorMessage(got.String(), want))
@@ -320,7 +338,7 @@ func TestNewLine(t *testing.T) {
 		md.NewLine(),
 		md.BoldText("bold"),
 	)
-	want := `\#serveral\_words\\n[link](golang.org)\\n*bold*`
+	want := "\\#serveral\\_words\n[link](golang.org)\n*bold*"
 
 	if !got.Equals(want) {
 		t.Errorf(errorMessage(got.String(), want))
@@ -335,3 +353,30 @@ func TestGetString(t *testing.T) {
 		t.Errorf(errorMessage(got.String(), want))
 	}
 }
+
+func TestLink(t *testing.T) {
+	got := md.Link("https://example.com/a_(b)", md.BoldText("bold"), md.Text(" link."))
+	want := `[*bold* link\.](https://example.com/a_(b\))`
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestBlockquote(t *testing.T) {
+	got := md.Blockquote(md.CombineWithNewLine(md.Text("first > line"), md.BoldText("second")))
+	want := ">first \\> line\n>*second*"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
+
+func TestNestedBlockquoteIsFlattened(t *testing.T) {
+	got := md.Blockquote(md.Text("outer"), md.NewLine(), md.Blockquote(md.Text("inner")))
+	want := ">outer\n>inner"
+
+	if !got.Equals(want) {
+		t.Errorf(errorMessage(got.String(), want))
+	}
+}
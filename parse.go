@@ -0,0 +1,300 @@
+package telegrammarkdown
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	boldNode
+	italicNode
+	underlineNode
+	strikethroughNode
+	spoilerNode
+	linkNode
+	codeNode
+	preNode
+	quoteNode
+)
+
+var nodeMarkers = map[nodeKind]string{
+	boldNode:          "*",
+	italicNode:        "_",
+	underlineNode:     "__",
+	strikethroughNode: "~",
+	spoilerNode:       "||",
+}
+
+// node is a parsed MarkdownV2 entity. Text, code and pre nodes hold their
+// unescaped content in text, links hold their unescaped URL in url.
+type node struct {
+	kind     nodeKind
+	text     string
+	url      string
+	language string
+	children []*node
+}
+
+func (n *node) appendText(text string) {
+	if last := len(n.children) - 1; last >= 0 && n.children[last].kind == textNode {
+		n.children[last].text += text
+		return
+	}
+	n.children = append(n.children, &node{kind: textNode, text: text})
+}
+
+type parser struct {
+	input       string
+	pos         int
+	stack       []*node
+	afterItalic int // position right after the last italic marker
+}
+
+func parseNodes(input string) []*node {
+	p := parser{input: input, stack: []*node{{}}, afterItalic: -1}
+	for p.pos < len(p.input) {
+		p.step()
+	}
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].kind == linkNode {
+			p.stack[i-1].unwrapLink(p.stack[i])
+		}
+	}
+	return p.stack[0].children
+}
+
+// unwrapLink turns a link that was never closed back into literal text.
+func (n *node) unwrapLink(link *node) {
+	for i, child := range n.children {
+		if child != link {
+			continue
+		}
+		unwrapped := append([]*node{{kind: textNode, text: "["}}, link.children...)
+		rest := append(unwrapped, n.children[i+1:]...)
+		n.children = append(n.children[:i], rest...)
+		return
+	}
+}
+
+func (p *parser) top() *node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.input[p.pos:], prefix)
+}
+
+func (p *parser) step() {
+	ch := p.input[p.pos]
+	switch {
+	case ch == '\r' && p.pos == p.afterItalic && p.hasPrefix("\r_"):
+		p.pos++
+		return
+	case ch == '\\' && p.pos+1 < len(p.input):
+		_, size := utf8.DecodeRuneInString(p.input[p.pos+1:])
+		p.top().appendText(p.input[p.pos+1 : p.pos+1+size])
+		p.pos += 1 + size
+		return
+	case p.hasPrefix("```"):
+		if p.pre() {
+			return
+		}
+	case ch == '`':
+		if p.code() {
+			return
+		}
+	case ch == '*':
+		p.toggle(boldNode, 1)
+		return
+	case ch == '~':
+		p.toggle(strikethroughNode, 1)
+		return
+	case p.hasPrefix("||"):
+		p.toggle(spoilerNode, 2)
+		return
+	case ch == '_':
+		if p.hasPrefix("__") {
+			p.toggle(underlineNode, 2)
+		} else {
+			p.toggle(italicNode, 1)
+		}
+		return
+	case ch == '[':
+		link := &node{kind: linkNode}
+		p.top().children = append(p.top().children, link)
+		p.stack = append(p.stack, link)
+		p.pos++
+		return
+	case ch == ']' && p.hasPrefix("](") && p.open(linkNode) >= 0:
+		if p.link() {
+			return
+		}
+	case ch == '>' && (p.pos == 0 || p.input[p.pos-1] == '\n'):
+		p.top().children = append(p.top().children, &node{kind: quoteNode})
+		p.pos++
+		return
+	}
+
+	_, size := utf8.DecodeRuneInString(p.input[p.pos:])
+	p.top().appendText(p.input[p.pos : p.pos+size])
+	p.pos += size
+}
+
+func (p *parser) open(kind nodeKind) int {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *parser) toggle(kind nodeKind, size int) {
+	p.pos += size
+	if kind == italicNode {
+		p.afterItalic = p.pos
+	}
+	if i := p.open(kind); i >= 0 {
+		p.stack = p.stack[:i]
+		return
+	}
+	entity := &node{kind: kind}
+	p.top().children = append(p.top().children, entity)
+	p.stack = append(p.stack, entity)
+}
+
+// until returns the unescaped text up to the next unescaped occurrence of
+// delimiter, and the position right after the delimiter.
+func (p *parser) until(from int, delimiter string) (string, int, bool) {
+	var text strings.Builder
+	for i := from; i < len(p.input); i++ {
+		switch {
+		case p.input[i] == '\\' && i+1 < len(p.input):
+			i++
+			text.WriteByte(p.input[i])
+		case strings.HasPrefix(p.input[i:], delimiter):
+			return text.String(), i + len(delimiter), true
+		default:
+			text.WriteByte(p.input[i])
+		}
+	}
+	return "", 0, false
+}
+
+func (p *parser) code() bool {
+	text, end, ok := p.until(p.pos+1, "`")
+	if !ok {
+		return false
+	}
+	p.top().children = append(p.top().children, &node{kind: codeNode, text: text})
+	p.pos = end
+	return true
+}
+
+func (p *parser) pre() bool {
+	start := p.pos + 3
+	language := ""
+	if newline := strings.IndexByte(p.input[start:], '\n'); newline >= 0 {
+		candidate := p.input[start : start+newline]
+		if !strings.ContainsAny(candidate, " \t`\\") {
+			language = candidate
+			start += newline + 1
+		}
+	}
+	text, end, ok := p.until(start, "```")
+	if !ok {
+		return false
+	}
+	p.top().children = append(p.top().children, &node{kind: preNode, text: text, language: language})
+	p.pos = end
+	return true
+}
+
+func (p *parser) link() bool {
+	url, end, ok := p.until(p.pos+2, ")")
+	if !ok {
+		return false
+	}
+	i := p.open(linkNode)
+	p.stack[i].url = url
+	p.stack = p.stack[:i]
+	p.pos = end
+	return true
+}
+
+func render(nodes []*node) string {
+	var out strings.Builder
+	writeNodes(&out, nodes)
+	return out.String()
+}
+
+// enclose wraps input in an entity of the given kind. Entities of the same
+// kind inside input are dropped, as MarkdownV2 cannot nest them.
+func enclose(kind nodeKind, input ...styledText) styledText {
+	children := flatten(parseNodes(getCombinedText(input...)), kind)
+	return styledText{text: render([]*node{{kind: kind, children: children}}), escaped: true}
+}
+
+func flatten(nodes []*node, kind nodeKind) []*node {
+	flat := make([]*node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.kind == kind {
+			flat = append(flat, flatten(n.children, kind)...)
+			continue
+		}
+		n.children = flatten(n.children, kind)
+		flat = append(flat, n)
+	}
+	return flat
+}
+
+func writeNodes(out *strings.Builder, nodes []*node) {
+	w := nodeWriter{out: out, afterItalic: -1}
+	w.nodes(nodes)
+}
+
+// nodeWriter separates an italic marker from a following '_' marker with
+// '\r', which Telegram ignores, as it reads "__" as an underline marker
+// wherever it appears.
+type nodeWriter struct {
+	out         *strings.Builder
+	afterItalic int
+}
+
+func (w *nodeWriter) marker(marker string) {
+	if marker[0] == '_' && w.out.Len() == w.afterItalic {
+		w.out.WriteString("\r")
+	}
+	w.out.WriteString(marker)
+	if marker == "_" {
+		w.afterItalic = w.out.Len()
+	}
+}
+
+func (w *nodeWriter) nodes(nodes []*node) {
+	out := w.out
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			out.WriteString(escape(n.text, escapes))
+		case codeNode:
+			out.WriteString("`" + escape(n.text, codeEscapes) + "`")
+		case preNode:
+			out.WriteString("```" + n.language + "\n" + escape(n.text, codeEscapes) + "```")
+		case quoteNode:
+			out.WriteString(">")
+		case linkNode:
+			out.WriteString("[")
+			w.nodes(n.children)
+			out.WriteString("](" + escape(n.url, urlEscapes) + ")")
+		default:
+			marker := nodeMarkers[n.kind]
+			w.marker(marker)
+			w.nodes(n.children)
+			w.marker(marker)
+		}
+	}
+}
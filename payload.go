@@ -0,0 +1,322 @@
+package telegrammarkdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	elision          = "…"
+	minElidedString  = 8
+	defaultMaxString = 64
+	defaultMaxArray  = 10
+)
+
+type redactedValue struct{}
+
+func (redactedValue) MarshalJSON() ([]byte, error) {
+	return []byte(`"[REDACTED]"`), nil
+}
+
+type Payload struct {
+	YAML      bool
+	Redact    []string
+	MaxArray  int
+	MaxString int
+	MaxDepth  int
+	Budget    int
+}
+
+func JSONBlock(v interface{}) (styledText, error) {
+	return Payload{}.Block(v)
+}
+
+func YAMLBlock(v interface{}) (styledText, error) {
+	return Payload{YAML: true}.Block(v)
+}
+
+func (p Payload) Block(v interface{}) (styledText, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return styledText{}, err
+	}
+	tree = p.redact(tree, nil)
+
+	block, err := p.render(tree)
+	for err == nil && p.Budget > 0 && utf8.RuneCountInString(block.text) > p.Budget {
+		tightened, ok := p.tighten(tree)
+		if !ok {
+			return p.cut(block)
+		}
+		p = tightened
+		block, err = p.render(tree)
+	}
+	return block, err
+}
+
+func toTree(v interface{}) (interface{}, error) {
+	var raw []byte
+	switch value := v.(type) {
+	case json.RawMessage:
+		raw = value
+	case []byte:
+		raw = value
+	default:
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (p Payload) redact(node interface{}, path []string) interface{} {
+	for _, pattern := range p.Redact {
+		if matchPath(strings.Split(pattern, "."), path) {
+			return redactedValue{}
+		}
+	}
+	switch value := node.(type) {
+	case map[string]interface{}:
+		redactedMap := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			redactedMap[k] = p.redact(v, append(path, k))
+		}
+		return redactedMap
+	case []interface{}:
+		redactedSlice := make([]interface{}, len(value))
+		for i, v := range value {
+			redactedSlice[i] = p.redact(v, append(path, strconv.Itoa(i)))
+		}
+		return redactedSlice
+	}
+	return node
+}
+
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Payload) elide(node interface{}, depth int) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if p.MaxDepth > 0 && depth >= p.MaxDepth && len(value) > 0 {
+			return fmt.Sprintf("{%s %d keys}", elision, len(value))
+		}
+		elided := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			elided[k] = p.elide(v, depth+1)
+		}
+		return elided
+	case []interface{}:
+		if p.MaxDepth > 0 && depth >= p.MaxDepth && len(value) > 0 {
+			return fmt.Sprintf("[%s %d items]", elision, len(value))
+		}
+		kept := value
+		if p.MaxArray > 0 && len(value) > p.MaxArray {
+			kept = value[:p.MaxArray]
+		}
+		elided := make([]interface{}, 0, len(kept)+1)
+		for _, v := range kept {
+			elided = append(elided, p.elide(v, depth+1))
+		}
+		if len(kept) < len(value) {
+			elided = append(elided, fmt.Sprintf("%s %d more", elision, len(value)-len(kept)))
+		}
+		return elided
+	case string:
+		if p.MaxString > 0 && utf8.RuneCountInString(value) > p.MaxString {
+			return string([]rune(value)[:p.MaxString]) + elision
+		}
+	}
+	return node
+}
+
+func (p Payload) render(tree interface{}) (styledText, error) {
+	tree = p.elide(tree, 0)
+	if p.YAML {
+		return CodeBlock("yaml", yamlLines(tree, "")), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(tree); err != nil {
+		return styledText{}, err
+	}
+	return CodeBlock("json", buf.String()), nil
+}
+
+func (p Payload) tighten(tree interface{}) (Payload, bool) {
+	switch {
+	case p.MaxString == 0:
+		p.MaxString = defaultMaxString
+	case p.MaxArray == 0:
+		p.MaxArray = defaultMaxArray
+	case p.MaxString > minElidedString:
+		p.MaxString /= 2
+	case p.MaxArray > 1:
+		p.MaxArray /= 2
+	case p.MaxDepth == 0:
+		p.MaxDepth = treeDepth(tree)
+	case p.MaxDepth > 1:
+		p.MaxDepth--
+	default:
+		return p, false
+	}
+	return p, true
+}
+
+// cut shortens the content of block to fit the budget. Budgets that cannot
+// hold an empty block with the elision mark are an error.
+func (p Payload) cut(block styledText) (styledText, error) {
+	language := "json"
+	if p.YAML {
+		language = "yaml"
+	}
+	overhead := utf8.RuneCountInString(CodeBlock(language, elision+"\n").text)
+	if p.Budget < overhead {
+		return styledText{}, fmt.Errorf("budget of %d characters is less than the %d of an elided %s block", p.Budget, overhead, language)
+	}
+	content := []rune(block.text)
+	content = content[len([]rune("```"+language+"\n")) : len(content)-len("```\n")]
+
+	keep := p.Budget - overhead
+	if keep > len(content) {
+		keep = len(content)
+	}
+	cut := string(content[:keep])
+	if trailing := len(cut) - len(strings.TrimRight(cut, `\`)); trailing%2 == 1 {
+		cut = cut[:len(cut)-1]
+	}
+	return styledText{
+		text:    encloseText(cut+elision, "```"+language+"\n", "\n```\n"),
+		escaped: true,
+	}, nil
+}
+
+func treeDepth(node interface{}) int {
+	depth := 0
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for _, v := range value {
+			depth = maxInt(depth, treeDepth(v))
+		}
+		return depth + 1
+	case []interface{}:
+		for _, v := range value {
+			depth = maxInt(depth, treeDepth(v))
+		}
+		return depth + 1
+	}
+	return 0
+}
+
+func yamlLines(node interface{}, indent string) string {
+	var out strings.Builder
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return indent + "{}\n"
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out.WriteString(indent + yamlScalar(k) + ":")
+			if isYAMLCollection(value[k]) {
+				out.WriteString("\n" + yamlLines(value[k], indent+"  "))
+			} else {
+				out.WriteString(" " + yamlScalar(value[k]) + "\n")
+			}
+		}
+	case []interface{}:
+		if len(value) == 0 {
+			return indent + "[]\n"
+		}
+		for _, v := range value {
+			if !isYAMLCollection(v) {
+				out.WriteString(indent + "- " + yamlScalar(v) + "\n")
+				continue
+			}
+			nested := yamlLines(v, indent+"  ")
+			out.WriteString(indent + "- " + strings.TrimPrefix(nested, indent+"  "))
+		}
+	default:
+		return indent + yamlScalar(value) + "\n"
+	}
+	return out.String()
+}
+
+func isYAMLCollection(node interface{}) bool {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		return len(value) > 0
+	case []interface{}:
+		return len(value) > 0
+	}
+	return false
+}
+
+func yamlScalar(node interface{}) string {
+	switch value := node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(value)
+	case json.Number:
+		return value.String()
+	case redactedValue:
+		return `"[REDACTED]"`
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	case string:
+		if yamlNeedsQuotes(value) {
+			return strconv.Quote(value)
+		}
+		return value
+	}
+	return fmt.Sprint(node)
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	return strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.ContainsAny(s, "\n\t")
+}
@@ -0,0 +1,118 @@
+package telegrammarkdown_test
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestJSONBlock(t *testing.T) {
+	got, err := md.JSONBlock(map[string]interface{}{"b": []int{1, 2}, "a": "x<y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "```json\n"
+	want += "{\n"
+	want += "  \"a\": \"x<y\",\n"
+	want += "  \"b\": [\n"
+	want += "    1,\n"
+	want += "    2\n"
+	want += "  ]\n"
+	want += "}\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestYAMLBlock(t *testing.T) {
+	raw := json.RawMessage(`{"name":"api","ports":[80,443],"env":[{"key":"A","value":"true"}],"empty":{}}`)
+	got, err := md.YAMLBlock(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "```yaml\n"
+	want += "empty: {}\n"
+	want += "env:\n"
+	want += "  - key: A\n"
+	want += "    value: \"true\"\n"
+	want += "name: api\n"
+	want += "ports:\n"
+	want += "  - 80\n"
+	want += "  - 443\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestPayloadRedactAndElide(t *testing.T) {
+	payload := md.Payload{
+		YAML:      true,
+		Redact:    []string{"auth.token", "users.*.password"},
+		MaxArray:  1,
+		MaxString: 3,
+	}
+	raw := []byte(`{"auth":{"token":"secret"},"users":[{"name":"alice","password":"p"},{"name":"bob","password":"q"}]}`)
+
+	got, err := payload.Block(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "```yaml\n"
+	want += "auth:\n"
+	want += "  token: \"[REDACTED]\"\n"
+	want += "users:\n"
+	want += "  - name: ali…\n"
+	want += "    password: \"[REDACTED]\"\n"
+	want += "  - … 1 more\n"
+	want += "```\n"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestPayloadBudget(t *testing.T) {
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = "a fairly long string value that takes up space"
+	}
+
+	for _, budget := range []int{60, 200, 1000} {
+		got, err := md.Payload{Budget: budget}.Block(map[string]interface{}{"values": values})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := utf8.RuneCountInString(got.String()); n > budget {
+			t.Errorf("budget %d exceeded: %d\n%s", budget, n, got)
+		}
+	}
+}
+
+func TestPayloadBudgetTooSmall(t *testing.T) {
+	value := map[string]interface{}{"key": "value"}
+
+	if got, err := (md.Payload{Budget: 5}).Block(value); err == nil {
+		t.Errorf("expected error, got %q", got)
+	}
+
+	got, err := md.Payload{Budget: 14}.Block(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "```json\n…\n```\n"
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestPayloadInvalidJSON(t *testing.T) {
+	if _, err := md.JSONBlock([]byte("{")); err == nil {
+		t.Error("expected error")
+	}
+}
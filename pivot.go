@@ -0,0 +1,169 @@
+package telegrammarkdown
+
+import (
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+type Aggregation func(values []float64) float64
+
+func AggregateSum(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func AggregateCount(values []float64) float64 {
+	return float64(len(values))
+}
+
+func AggregateAverage(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return AggregateSum(values) / float64(len(values))
+}
+
+func AggregateMin(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func AggregateMax(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// Pivot accessors take a record index, in the manner of sort.Slice.
+type Pivot struct {
+	RowKey       func(i int) string
+	ColumnKey    func(i int) string
+	Value        func(i int) float64
+	Aggregate    Aggregation
+	Format       func(v float64) string
+	RowHeader    string
+	TotalLabel   string
+	RowTotals    bool
+	ColumnTotals bool
+	SortKeys     bool
+	Separator    string
+	CodeBlock    bool
+	Margin       uint
+}
+
+func (p Pivot) Table(n int) *Table {
+	aggregate := p.Aggregate
+	if aggregate == nil {
+		aggregate = AggregateSum
+	}
+	format := p.Format
+	if format == nil {
+		format = func(v float64) string {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	totalLabel := p.TotalLabel
+	if totalLabel == "" {
+		totalLabel = "Total"
+	}
+
+	var rowKeys, columnKeys []string
+	seenRows := map[string]bool{}
+	seenColumns := map[string]bool{}
+	cells := map[[2]string][]float64{}
+	rowValues := map[string][]float64{}
+	columnValues := map[string][]float64{}
+	var allValues []float64
+
+	for i := 0; i < n; i++ {
+		row, column, value := p.RowKey(i), p.ColumnKey(i), p.Value(i)
+		if !seenRows[row] {
+			seenRows[row] = true
+			rowKeys = append(rowKeys, row)
+		}
+		if !seenColumns[column] {
+			seenColumns[column] = true
+			columnKeys = append(columnKeys, column)
+		}
+		key := [2]string{row, column}
+		cells[key] = append(cells[key], value)
+		rowValues[row] = append(rowValues[row], value)
+		columnValues[column] = append(columnValues[column], value)
+		allValues = append(allValues, value)
+	}
+	if p.SortKeys {
+		sort.Strings(rowKeys)
+		sort.Strings(columnKeys)
+	}
+
+	header := append([]string{p.RowHeader}, columnKeys...)
+	if p.RowTotals {
+		header = append(header, totalLabel)
+	}
+
+	var body [][]string
+	for _, row := range rowKeys {
+		line := []string{row}
+		for _, column := range columnKeys {
+			values, ok := cells[[2]string{row, column}]
+			if !ok {
+				line = append(line, "")
+				continue
+			}
+			line = append(line, format(aggregate(values)))
+		}
+		if p.RowTotals {
+			line = append(line, format(aggregate(rowValues[row])))
+		}
+		body = append(body, line)
+	}
+	if p.ColumnTotals {
+		line := []string{totalLabel}
+		for _, column := range columnKeys {
+			line = append(line, format(aggregate(columnValues[column])))
+		}
+		if p.RowTotals {
+			line = append(line, format(aggregate(allValues)))
+		}
+		body = append(body, line)
+	}
+
+	table := &Table{Separator: p.Separator, CodeBlock: p.CodeBlock}
+	for i := range header {
+		width := utf8.RuneCountInString(header[i])
+		for _, line := range body {
+			if w := utf8.RuneCountInString(line[i]); w > width {
+				width = w
+			}
+		}
+		column := Column{Header: header[i], Width: width, Margin: p.Margin}
+		if i == 0 {
+			column.Align = Left
+		}
+		table.AddColumns(column)
+	}
+	table.SetHeader(header...)
+	for _, line := range body {
+		table.AddRow(line...)
+	}
+	return table
+}
@@ -0,0 +1,76 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+type sale struct {
+	region string
+	month  string
+	value  float64
+}
+
+var sales = []sale{
+	{"north", "jan", 10},
+	{"south", "jan", 5},
+	{"north", "feb", 7},
+	{"north", "jan", 3},
+}
+
+func salesPivot() md.Pivot {
+	return md.Pivot{
+		RowKey:    func(i int) string { return sales[i].region },
+		ColumnKey: func(i int) string { return sales[i].month },
+		Value:     func(i int) float64 { return sales[i].value },
+		RowHeader: "region",
+		Separator: "|",
+	}
+}
+
+func TestPivotSum(t *testing.T) {
+	table := salesPivot().Table(len(sales))
+
+	got := table.String()
+	want := "`region|jan|feb`\n"
+	want += "`north | 13|  7`\n"
+	want += "`south |  5|   `"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestPivotTotals(t *testing.T) {
+	pivot := salesPivot()
+	pivot.RowTotals = true
+	pivot.ColumnTotals = true
+	pivot.SortKeys = true
+	table := pivot.Table(len(sales))
+
+	got := table.String()
+	want := "`region|feb|jan|Total`\n"
+	want += "`north |  7| 13|   20`\n"
+	want += "`south |   |  5|    5`\n"
+	want += "`Total |  7| 18|   25`"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestPivotCount(t *testing.T) {
+	pivot := salesPivot()
+	pivot.Aggregate = md.AggregateCount
+	table := pivot.Table(len(sales))
+
+	got := table.String()
+	want := "`region|jan|feb`\n"
+	want += "`north |  2|  1`\n"
+	want += "`south |  1|   `"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
@@ -0,0 +1,57 @@
+package telegrammarkdown
+
+import "strings"
+
+type PlainOptions struct {
+	// AppendURLs writes the URL of a link in parentheses after its text,
+	// unless the text already is the URL.
+	AppendURLs bool
+}
+
+func Plain(input styledText) string {
+	return PlainOptions{}.Plain(input)
+}
+
+func PlainText(input string) string {
+	return PlainOptions{}.PlainText(input)
+}
+
+func (o PlainOptions) Plain(input styledText) string {
+	input.escape()
+	return o.PlainText(input.text)
+}
+
+func (o PlainOptions) PlainText(input string) string {
+	var out strings.Builder
+	o.writeNodes(&out, parseNodes(input))
+	return out.String()
+}
+
+func (o PlainOptions) writeNodes(out *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode, codeNode:
+			out.WriteString(n.text)
+		case preNode:
+			out.WriteString(strings.TrimSuffix(n.text, "\n"))
+		case quoteNode:
+		case linkNode:
+			start := out.Len()
+			o.writeNodes(out, n.children)
+			if o.AppendURLs && out.String()[start:] != n.url {
+				out.WriteString(" (" + n.url + ")")
+			}
+		default:
+			o.writeNodes(out, n.children)
+		}
+	}
+}
+
+func (t *Table) Plain() string {
+	rows := t.allRows()
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = t.formatRow(row)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,67 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestPlain(t *testing.T) {
+	input := md.CombineWithSpace(
+		md.Bold(md.Text("Deploy"), md.ItalicText(" v1.2.3!")),
+		md.UnderlineText("done"),
+		md.SpoilerText("secret"),
+		md.InlineFixWidth("a_b`c"),
+		md.InlineURL("dashboard", "https://example.com/(x)"),
+	)
+
+	got := md.Plain(input)
+	want := "Deploy v1.2.3! done secret a_b`c dashboard"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestPlainAppendURLs(t *testing.T) {
+	input := md.CombineWithSpace(
+		md.InlineURL("dashboard", "https://example.com/(x)"),
+		md.InlineURL("https://example.com", "https://example.com"),
+	)
+
+	got := md.PlainOptions{AppendURLs: true}.Plain(input)
+	want := "dashboard (https://example.com/(x)) https://example.com"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := md.PlainText(">quoted *bold* \\> text\n```go\nfmt.Println(\"\\`x\\`\")\n```")
+	want := "quoted bold > text\nfmt.Println(\"`x`\")"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestPlainTable(t *testing.T) {
+	table := md.Table{Separator: "|"}
+	table.AddColumns(md.Column{Width: 6, Align: md.Left}, md.Column{Width: 4})
+	table.SetHeader("name", "n")
+	table.AddRow("a.b", "1")
+
+	want := "name  |   n\na.b   |   1"
+	if got := md.Plain(table.Styled()); got != want {
+		t.Error(errorMessage(got, want))
+	}
+
+	table.CodeBlock = true
+	if got := md.Plain(table.Styled()); got != want {
+		t.Error(errorMessage(got, want))
+	}
+	if got := table.Plain(); got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
@@ -0,0 +1,152 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sprintf formats according to a trusted MarkdownV2 format string and escapes
+// every argument for the context its verb appears in. styledText arguments
+// printed with %s or %v are inserted verbatim in text, padded to any width.
+// Other verbs, and precisions, format their plain text.
+func Sprintf(format string, args ...interface{}) styledText {
+	f := formatter{args: args}
+	var out strings.Builder
+	c := contextText
+	for i := 0; i < len(format); {
+		percent := strings.IndexByte(format[i:], '%')
+		if percent < 0 {
+			percent = len(format) - i
+		}
+		literal := format[i : i+percent]
+		out.WriteString(literal)
+		c = c.advance(literal)
+		i += percent
+		if i >= len(format) {
+			break
+		}
+		if strings.HasPrefix(format[i:], "%%") {
+			out.WriteString("%")
+			i += 2
+			continue
+		}
+		var value string
+		value, i = f.verb(format, i+1, c)
+		out.WriteString(value)
+	}
+	if !f.reordered && f.argNum < len(args) {
+		extra := make([]string, 0, len(args)-f.argNum)
+		for _, arg := range args[f.argNum:] {
+			if arg == nil {
+				extra = append(extra, "<nil>")
+				continue
+			}
+			extra = append(extra, fmt.Sprintf("%T=%v", arg, arg))
+		}
+		out.WriteString(c.escape("%!(EXTRA " + strings.Join(extra, ", ") + ")"))
+	}
+	return styledText{text: out.String(), escaped: true}
+}
+
+type formatter struct {
+	args      []interface{}
+	argNum    int
+	reordered bool
+}
+
+func (f *formatter) next() (interface{}, bool) {
+	if f.argNum >= len(f.args) {
+		return nil, false
+	}
+	f.argNum++
+	return f.args[f.argNum-1], true
+}
+
+func (f *formatter) index(format string, i int) int {
+	if i >= len(format) || format[i] != '[' {
+		return i
+	}
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return i
+	}
+	if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil && n > 0 {
+		f.argNum = n - 1
+		f.reordered = true
+	}
+	return i + end + 1
+}
+
+// verb formats the single verb starting right after the '%' at format[i:]
+// and returns the escaped result and the position following the verb.
+func (f *formatter) verb(format string, i int, c escapeContext) (string, int) {
+	spec := "%"
+	var verbArgs []interface{}
+
+	start := i
+	for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+		i++
+	}
+	spec += format[start:i]
+
+	number := func() {
+		i = f.index(format, i)
+		if i < len(format) && format[i] == '*' {
+			i++
+			if arg, ok := f.next(); ok {
+				verbArgs = append(verbArgs, arg)
+			}
+			spec += "*"
+			return
+		}
+		start := i
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		spec += format[start:i]
+	}
+	number()
+	if i < len(format) && format[i] == '.' {
+		spec += "."
+		i++
+		number()
+	}
+	i = f.index(format, i)
+
+	if i >= len(format) {
+		return c.escape("%!(NOVERB)"), i
+	}
+	verb, size := utf8.DecodeRuneInString(format[i:])
+	i += size
+	spec += string(verb)
+
+	arg, ok := f.next()
+	if !ok {
+		return c.escape(fmt.Sprintf(spec, verbArgs...)), i
+	}
+	if styled, isStyled := arg.(styledText); isStyled {
+		return formatStyled(spec, verbArgs, styled, c), i
+	}
+	return c.escape(fmt.Sprintf(spec, append(verbArgs, arg)...)), i
+}
+
+// formatStyled formats the plain text of styled. When %s or %v only pads it
+// in text, the styled text is kept and the padding is written around it.
+func formatStyled(spec string, verbArgs []interface{}, styled styledText, c escapeContext) string {
+	plain := Plain(styled)
+	formatted := fmt.Sprintf(spec, append(verbArgs, plain)...)
+	verb := spec[len(spec)-1]
+	if (verb != 's' && verb != 'v') || (c != contextText && c != contextLinkEnd) {
+		return c.escape(formatted)
+	}
+	padding := len(formatted) - len(plain)
+	switch {
+	case strings.HasPrefix(formatted, plain) && strings.TrimRight(formatted[len(plain):], " ") == "":
+		return c.escape(styled) + formatted[len(plain):]
+	case strings.HasSuffix(formatted, plain) && strings.TrimLeft(formatted[:padding], " 0") == "":
+		return formatted[:padding] + c.escape(styled)
+	}
+	return c.escape(formatted)
+}
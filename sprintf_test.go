@@ -0,0 +1,68 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestSprintf(t *testing.T) {
+	got := md.Sprintf("*%s* deployed %s to `%s`", "john.doe", "v1.2", "prod`eu")
+	want := "*john\\.doe* deployed v1\\.2 to `prod\\`eu`"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSprintfStyledArguments(t *testing.T) {
+	got := md.Sprintf("%s by %v in `%s`", md.BoldText("a.b"), md.ItalicText("c"), md.BoldText("d*e"))
+	want := "*a\\.b* by _c_ in `d*e`"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSprintfPaddedStyledArguments(t *testing.T) {
+	got := md.Sprintf("%-6s\\|%6v\\|%q\\|%.3s", md.BoldText("a.b"), md.ItalicText("c"), md.BoldText("d"), md.BoldText("e.fgh"))
+	want := "*a\\.b*   \\|     _c_\\|\"d\"\\|e\\.f"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSprintfLinkURL(t *testing.T) {
+	got := md.Sprintf("[%s](%s)", "docs (v2)", "https://example.com/a_(b)")
+	want := "[docs \\(v2\\)](https://example.com/a_(b\\))"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSprintfVerbs(t *testing.T) {
+	got := md.Sprintf("%d%% %.2f %-4s\\| %*d %[1]d", 50, 1.5, "ab", 3, 7)
+	want := "50% 1\\.50 ab  \\|   7 50"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSprintfMissingAndExtra(t *testing.T) {
+	got := md.Sprintf("%s", "a", 1)
+	want := "a%\\!\\(EXTRA int\\=1\\)"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+
+	got = md.Sprintf("%s %d", "a")
+	want = "a %\\!d\\(MISSING\\)"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
@@ -1,8 +1,9 @@
 package telegrammarkdown
 
 import (
-	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 type Alignment int
@@ -20,22 +21,42 @@ type Column struct {
 	Margin uint
 }
 
-func (c Column) getCellFormat() string {
-	width := c.Width
+func (c Column) writeCell(w io.StringWriter, text string, e *escaper) {
+	padding := c.Width - utf8.RuneCountInString(text)
+	if padding < 0 {
+		padding = 0
+	}
+	left := padding
 	if c.Align == Left {
-		width = -width
+		left = 0
+	}
+	writeSpaces(w, int(c.Margin)+left)
+	e.write(w, text)
+	writeSpaces(w, padding-left+int(c.Margin))
+}
+
+type Cell struct {
+	Text string
+	Span int
+}
+
+func (c Cell) span() int {
+	if c.Span < 1 {
+		return 1
 	}
-	margin := strings.Repeat(" ", int(c.Margin))
-	format := fmt.Sprintf("%s%%%ds%s", margin, width, margin)
-	return format
+	return c.Span
 }
 
+type tableRow []Cell
+
 type Table struct {
-	Separator string
-	CodeBlock bool
-	columns   []Column
-	rows      [][]string
-	header    []string
+	Separator  string
+	CodeBlock  bool
+	Language   string
+	columns    []Column
+	rows       []tableRow
+	header     tableRow
+	headerRows []tableRow
 }
 
 func (t *Table) AddColumns(columns ...Column) {
@@ -43,61 +64,138 @@ func (t *Table) AddColumns(columns ...Column) {
 }
 
 func (t *Table) AddRow(cells ...string) {
-	row := make([]string, 0, len(t.columns))
-	for i, col := range t.columns {
+	t.rows = append(t.rows, t.newRow(cells))
+}
+
+func (t *Table) AddSpanRow(cells ...Cell) {
+	t.rows = append(t.rows, t.newSpanRow(cells))
+}
+
+func (t *Table) SetHeader(cells ...string) {
+	t.header = t.newRow(cells)
+}
+
+func (t *Table) AddHeaderRow(cells ...Cell) {
+	t.headerRows = append(t.headerRows, t.newSpanRow(cells))
+}
+
+func (t *Table) newRow(cells []string) tableRow {
+	row := make(tableRow, 0, len(t.columns))
+	for i := range t.columns {
 		if i >= len(cells) {
 			break
 		}
-		row = append(row, fmt.Sprintf(col.getCellFormat(), cells[i]))
+		row = append(row, Cell{Text: cells[i], Span: 1})
 	}
-	t.rows = append(t.rows, row)
+	return row
 }
 
-func (t *Table) SetHeader(cells ...string) {
-	t.header = make([]string, 0, len(t.columns))
-	for i, col := range t.columns {
-		if i >= len(cells) {
+func (t *Table) newSpanRow(cells []Cell) tableRow {
+	row := make(tableRow, 0, len(cells))
+	used := 0
+	for _, cell := range cells {
+		if used >= len(t.columns) {
 			break
 		}
-		t.header = append(t.header, fmt.Sprintf(col.getCellFormat(), cells[i]))
+		if used+cell.span() > len(t.columns) {
+			cell.Span = len(t.columns) - used
+		}
+		row = append(row, cell)
+		used += cell.span()
 	}
+	return row
 }
 
-func (t *Table) String() string {
-	if t.CodeBlock {
-		return t.blockEscapedString()
+func (t *Table) formatRow(row tableRow) string {
+	var line strings.Builder
+	t.writeRow(&line, row, noEscaper)
+	return line.String()
+}
+
+func (t *Table) writeRow(w io.StringWriter, row tableRow, e *escaper) {
+	column := 0
+	for i, cell := range row {
+		if i > 0 {
+			e.write(w, t.Separator)
+		}
+		span := cell.span()
+		if span == 1 {
+			t.columns[column].writeCell(w, cell.Text, e)
+		} else {
+			t.writeSpan(w, cell.Text, t.columns[column:column+span], e)
+		}
+		column += span
 	}
-	return t.perColumnEscapedString()
 }
 
-func (t *Table) perColumnEscapedString() string {
-	var data string
-	add := func(row []string) {
-		joined := strings.Join(row, t.Separator)
-		escaped := escape(joined, "`\\")
-		data += encloseText(escaped, "`", "`") + "\n"
+func (t *Table) writeSpan(w io.StringWriter, text string, columns []Column, e *escaper) {
+	width := utf8.RuneCountInString(t.Separator) * (len(columns) - 1)
+	for _, col := range columns {
+		width += col.Width + 2*int(col.Margin)
 	}
-	if len(t.header) > 0 {
-		add(t.header)
+	first, last := columns[0], columns[len(columns)-1]
+	padding := width - int(first.Margin) - int(last.Margin) - utf8.RuneCountInString(text)
+	if padding < 0 {
+		padding = 0
 	}
-	for _, row := range t.rows {
-		add(row)
+	writeSpaces(w, int(first.Margin)+padding/2)
+	e.write(w, text)
+	writeSpaces(w, padding-padding/2+int(last.Margin))
+}
+
+func (t *Table) allRows() []tableRow {
+	rows := make([]tableRow, 0, len(t.headerRows)+len(t.rows)+1)
+	rows = append(rows, t.headerRows...)
+	if len(t.header) > 0 {
+		rows = append(rows, t.header)
 	}
-	return strings.TrimSuffix(data, "\n")
+	return append(rows, t.rows...)
+}
+
+func (t *Table) String() string {
+	var out strings.Builder
+	out.Grow(t.size())
+	t.render(&out)
+	return out.String()
 }
 
-func (t *Table) blockEscapedString() string {
-	var data string
-	add := func(row []string) {
-		joined := strings.Join(row, t.Separator)
-		data += escape(joined, "`\\") + "\n"
+func (t *Table) Styled() styledText {
+	return styledText{text: t.String(), escaped: true}
+}
+
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	out := &renderWriter{w: w}
+	t.render(out)
+	return out.n, out.err
+}
+
+// size estimates the length of the rendered table so that it can be written
+// without growing the buffer.
+func (t *Table) size() int {
+	width := 3 + len(t.Separator)*len(t.columns)
+	for _, col := range t.columns {
+		width += col.Width + 2*int(col.Margin)
 	}
-	if len(t.header) > 0 {
-		add(t.header)
+	return width*(len(t.rows)+len(t.headerRows)+1) + 8 + len(t.Language)
+}
+
+func (t *Table) render(w io.StringWriter) {
+	rows := t.allRows()
+	if t.CodeBlock {
+		w.WriteString("```" + t.Language + "\n")
+		for _, row := range rows {
+			t.writeRow(w, row, codeEscaper)
+			w.WriteString("\n")
+		}
+		w.WriteString("```")
+		return
 	}
-	for _, row := range t.rows {
-		add(row)
+	for i, row := range rows {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		w.WriteString("`")
+		t.writeRow(w, row, codeEscaper)
+		w.WriteString("`")
 	}
-	data = encloseText(data, "```\n", "```")
-	return data
 }
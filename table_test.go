@@ -188,3 +188,62 @@ func TestTableCodeBlock(t *testing.T) {
 		t.Error(errorMessage(got, want))
 	}
 }
+
+func TestTableHeaderRowWithSpan(t *testing.T) {
+	table := md.Table{Separator: "|"}
+	table.AddColumns(
+		md.Column{Width: 3},
+		md.Column{Width: 3},
+		md.Column{Width: 3},
+		md.Column{Width: 3},
+	)
+	table.AddHeaderRow(md.Cell{Text: "Q1", Span: 2}, md.Cell{Text: "Q2", Span: 2})
+	table.SetHeader("m1", "m2", "m3", "m4")
+	table.AddRow("1", "2", "3", "4")
+
+	got := table.String()
+	want := "`  Q1   |  Q2   `\n"
+	want += "` m1| m2| m3| m4`\n"
+	want += "`  1|  2|  3|  4`"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTableSpanRowWithMargin(t *testing.T) {
+	table := md.Table{Separator: "|", CodeBlock: true}
+	table.AddColumns(
+		md.Column{Width: 4, Margin: 1},
+		md.Column{Width: 4, Margin: 1},
+		md.Column{Width: 4, Margin: 1},
+	)
+	table.AddRow("a", "b", "c")
+	table.AddSpanRow(md.Cell{Text: "total", Span: 2}, md.Cell{Text: "c"})
+
+	got := table.String()
+	want := "```\n"
+	want += "    a |    b |    c \n"
+	want += "    total    |    c \n"
+	want += "```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTableSpanLongerThanColumns(t *testing.T) {
+	table := md.Table{}
+	table.AddColumns(
+		md.Column{Width: 2},
+		md.Column{Width: 2},
+	)
+	table.AddSpanRow(md.Cell{Text: "ab", Span: 5})
+
+	got := table.String()
+	want := "` ab `"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
@@ -0,0 +1,121 @@
+package telegrammarkdown
+
+import "unicode/utf8"
+
+const (
+	diffAdded     = "+"
+	diffRemoved   = "-"
+	diffChanged   = "!"
+	diffUnchanged = " "
+	diffArrow     = "→"
+)
+
+type TableDiff struct {
+	Key           int
+	HideUnchanged bool
+}
+
+func DiffTables(old, current *Table, key int) *Table {
+	return TableDiff{Key: key}.Diff(old, current)
+}
+
+func (d TableDiff) Diff(old, current *Table) *Table {
+	oldRows := old.texts()
+	newRows := current.texts()
+
+	oldIndex := make(map[string]int, len(oldRows))
+	for i, row := range oldRows {
+		oldIndex[cellAt(row, d.Key)] = i
+	}
+	newKeys := make(map[string]bool, len(newRows))
+	for _, row := range newRows {
+		newKeys[cellAt(row, d.Key)] = true
+	}
+
+	var marks []string
+	var body [][]string
+	emitted := 0
+	emitRemoved := func(until int) {
+		for ; emitted < until; emitted++ {
+			row := oldRows[emitted]
+			if !newKeys[cellAt(row, d.Key)] {
+				marks = append(marks, diffRemoved)
+				body = append(body, row)
+			}
+		}
+	}
+
+	for _, row := range newRows {
+		i, ok := oldIndex[cellAt(row, d.Key)]
+		if !ok {
+			marks = append(marks, diffAdded)
+			body = append(body, row)
+			continue
+		}
+		if i >= emitted {
+			emitRemoved(i + 1)
+		}
+		changed := false
+		merged := make([]string, len(current.columns))
+		for c := range merged {
+			before, after := cellAt(oldRows[i], c), cellAt(row, c)
+			merged[c] = after
+			if before != after {
+				changed = true
+				merged[c] = before + diffArrow + after
+			}
+		}
+		if changed {
+			marks = append(marks, diffChanged)
+			body = append(body, merged)
+		} else if !d.HideUnchanged {
+			marks = append(marks, diffUnchanged)
+			body = append(body, row)
+		}
+	}
+	emitRemoved(len(oldRows))
+
+	result := &Table{Separator: current.Separator, CodeBlock: true, Language: "diff"}
+	result.AddColumns(Column{Width: 1, Align: Left})
+	for c, col := range current.columns {
+		for _, row := range body {
+			if w := utf8.RuneCountInString(cellAt(row, c)); w > col.Width {
+				col.Width = w
+			}
+		}
+		result.AddColumns(col)
+	}
+	if len(current.header) > 0 {
+		header := []string{diffUnchanged}
+		for _, cell := range current.header {
+			header = append(header, cell.Text)
+		}
+		result.SetHeader(header...)
+	}
+	for i, row := range body {
+		result.AddRow(append([]string{marks[i]}, row...)...)
+	}
+	return result
+}
+
+func (t *Table) texts() [][]string {
+	rows := make([][]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		texts := make([]string, 0, len(t.columns))
+		for _, cell := range row {
+			texts = append(texts, cell.Text)
+			for i := 1; i < cell.span(); i++ {
+				texts = append(texts, "")
+			}
+		}
+		rows = append(rows, texts)
+	}
+	return rows
+}
+
+func cellAt(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
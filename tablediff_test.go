@@ -0,0 +1,61 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func statusTable(rows ...[]string) *md.Table {
+	table := &md.Table{Separator: " "}
+	table.AddColumns(
+		md.Column{Width: 3, Align: md.Left},
+		md.Column{Width: 4},
+	)
+	table.SetHeader("svc", "cpu")
+	for _, row := range rows {
+		table.AddRow(row...)
+	}
+	return table
+}
+
+func TestDiffTables(t *testing.T) {
+	old := statusTable(
+		[]string{"api", "10"},
+		[]string{"db", "20"},
+		[]string{"web", "30"},
+	)
+	current := statusTable(
+		[]string{"api", "10"},
+		[]string{"web", "35"},
+		[]string{"job", "5"},
+	)
+
+	got := md.DiffTables(old, current, 0).String()
+	want := "```diff\n"
+	want += "  svc   cpu\n"
+	want += "  api    10\n"
+	want += "- db     20\n"
+	want += "! web 30→35\n"
+	want += "+ job     5\n"
+	want += "```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestDiffTablesHideUnchanged(t *testing.T) {
+	old := statusTable([]string{"api", "10"}, []string{"db", "20"})
+	current := statusTable([]string{"api", "10"}, []string{"db", "25"})
+
+	got := md.TableDiff{Key: 0, HideUnchanged: true}.Diff(old, current).String()
+	want := "```diff\n"
+	want += "  svc   cpu\n"
+	want += "! db  20→25\n"
+	want += "```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
@@ -0,0 +1,188 @@
+package telegrammarkdown
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"unicode/utf8"
+)
+
+type BorderStyle int
+
+const (
+	BorderNone BorderStyle = iota
+	BorderOuter
+	BorderGrid
+)
+
+const (
+	charWidth  = glyphWidth + 1
+	lineHeight = glyphHeight + 4
+)
+
+type ImageStyle struct {
+	Scale            int
+	Padding          int
+	Foreground       color.Color
+	Background       color.Color
+	HeaderBackground color.Color
+	ZebraBackground  color.Color
+	BorderColor      color.Color
+	Zebra            bool
+	Border           BorderStyle
+}
+
+func (s ImageStyle) withDefaults() ImageStyle {
+	if s.Scale <= 0 {
+		s.Scale = 2
+	}
+	if s.Padding <= 0 {
+		s.Padding = 4 * s.Scale
+	}
+	if s.Foreground == nil {
+		s.Foreground = color.Black
+	}
+	if s.Background == nil {
+		s.Background = color.White
+	}
+	if s.HeaderBackground == nil {
+		s.HeaderBackground = color.Gray{Y: 0xdd}
+	}
+	if s.ZebraBackground == nil {
+		s.ZebraBackground = color.Gray{Y: 0xf3}
+	}
+	if s.BorderColor == nil {
+		s.BorderColor = color.Gray{Y: 0x99}
+	}
+	return s
+}
+
+func (t *Table) WritePNG(w io.Writer, style ImageStyle) error {
+	return png.Encode(w, t.Image(style))
+}
+
+func (t *Table) Image(style ImageStyle) *image.RGBA {
+	style = style.withDefaults()
+	scale, pad := style.Scale, style.Padding
+	cellW, cellH := charWidth*scale, lineHeight*scale
+
+	rows := t.allRows()
+	headerCount := len(rows) - len(t.rows)
+	lines := make([]string, len(rows))
+	columns := 0
+	for i, row := range rows {
+		lines[i] = t.formatRow(row)
+		if n := utf8.RuneCountInString(lines[i]); n > columns {
+			columns = n
+		}
+	}
+
+	width := 2*pad + columns*cellW
+	height := 2*pad + len(lines)*cellH
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, img.Bounds(), style.Background)
+
+	for i, line := range lines {
+		y := pad + i*cellH
+		switch {
+		case i < headerCount:
+			fillRect(img, image.Rect(pad, y, width-pad, y+cellH), style.HeaderBackground)
+		case style.Zebra && (i-headerCount)%2 == 1:
+			fillRect(img, image.Rect(pad, y, width-pad, y+cellH), style.ZebraBackground)
+		}
+		x := pad
+		for _, r := range line {
+			drawRune(img, r, x, y, scale, style.Foreground)
+			x += cellW
+		}
+	}
+
+	if style.Border == BorderNone {
+		return img
+	}
+	content := image.Rect(pad, pad, width-pad, height-pad).Inset(-scale)
+	drawFrame(img, content, scale, style.BorderColor)
+	if headerCount > 0 && headerCount < len(lines) {
+		y := pad + headerCount*cellH
+		fillRect(img, image.Rect(pad, y, width-pad, y+scale), style.BorderColor)
+	}
+	if style.Border != BorderGrid {
+		return img
+	}
+	for i, row := range rows {
+		y := pad + i*cellH
+		if i > 0 {
+			fillRect(img, image.Rect(pad, y, width-pad, y+scale), style.BorderColor)
+		}
+		for _, offset := range t.boundaries(row) {
+			x := pad + offset*cellW/2
+			fillRect(img, image.Rect(x, y, x+scale, y+cellH), style.BorderColor)
+		}
+	}
+	return img
+}
+
+// boundaries returns the position of the separators between the cells of a
+// row, in half characters so that odd-length separators can be centred.
+func (t *Table) boundaries(row tableRow) []int {
+	separator := utf8.RuneCountInString(t.Separator)
+	var offsets []int
+	position, column := 0, 0
+	for i, cell := range row {
+		for j := 0; j < cell.span(); j++ {
+			col := t.columns[column+j]
+			position += col.Width + 2*int(col.Margin)
+			if j > 0 {
+				position += separator
+			}
+		}
+		column += cell.span()
+		if i < len(row)-1 {
+			offsets = append(offsets, 2*position+separator)
+			position += separator
+		}
+	}
+	return offsets
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func drawFrame(img *image.RGBA, r image.Rectangle, thickness int, c color.Color) {
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+thickness), c)
+	fillRect(img, image.Rect(r.Min.X, r.Max.Y-thickness, r.Max.X, r.Max.Y), c)
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+thickness, r.Max.Y), c)
+	fillRect(img, image.Rect(r.Max.X-thickness, r.Min.Y, r.Max.X, r.Max.Y), c)
+}
+
+func drawRune(img *image.RGBA, r rune, x, y, scale int, c color.Color) {
+	cellW, cellH := charWidth*scale, lineHeight*scale
+	switch {
+	case r == ' ':
+		return
+	case r >= '▁' && r <= '█':
+		eighths := int(r-'▁') + 1
+		top := y + cellH - cellH*eighths/8
+		fillRect(img, image.Rect(x, top, x+cellW, y+cellH), c)
+		return
+	case r >= '▉' && r <= '▏':
+		eighths := 8 - int(r-'▉') - 1
+		fillRect(img, image.Rect(x, y, x+cellW*eighths/8, y+cellH), c)
+		return
+	}
+
+	g := glyph(r)
+	top := y + 2*scale
+	for col := 0; col < glyphWidth; col++ {
+		for row := 0; row < glyphHeight; row++ {
+			if g[col]&(1<<uint(row)) == 0 {
+				continue
+			}
+			px, py := x+col*scale, top+row*scale
+			fillRect(img, image.Rect(px, py, px+scale, py+scale), c)
+		}
+	}
+}
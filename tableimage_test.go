@@ -0,0 +1,82 @@
+package telegrammarkdown_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func imageTable() *md.Table {
+	table := &md.Table{Separator: " "}
+	table.AddColumns(
+		md.Column{Width: 4, Align: md.Left},
+		md.Column{Width: 4},
+	)
+	table.SetHeader("name", "cpu")
+	table.AddRow("api", "10")
+	table.AddRow("db", "20")
+	return table
+}
+
+func TestTableImageSize(t *testing.T) {
+	img := imageTable().Image(md.ImageStyle{Scale: 1, Padding: 2})
+
+	got := img.Bounds().Size()
+	if got.X != 2*2+9*6 || got.Y != 2*2+3*11 {
+		t.Errorf("unexpected size %v", got)
+	}
+}
+
+func TestTableImageShading(t *testing.T) {
+	header := color.RGBA{R: 0xff, A: 0xff}
+	zebra := color.RGBA{G: 0xff, A: 0xff}
+	img := imageTable().Image(md.ImageStyle{
+		Scale:            1,
+		Padding:          2,
+		HeaderBackground: header,
+		ZebraBackground:  zebra,
+		Zebra:            true,
+	})
+
+	if got := img.RGBAAt(2, 2); got != header {
+		t.Errorf("header pixel: got %v, want %v", got, header)
+	}
+	if got := img.RGBAAt(2, 2+11); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("first row pixel: got %v", got)
+	}
+	if got := img.RGBAAt(2, 2+22); got != zebra {
+		t.Errorf("zebra pixel: got %v, want %v", got, zebra)
+	}
+}
+
+func TestTableImageGridBorder(t *testing.T) {
+	border := color.RGBA{B: 0xff, A: 0xff}
+	img := imageTable().Image(md.ImageStyle{
+		Scale:       1,
+		Padding:     2,
+		BorderColor: border,
+		Border:      md.BorderGrid,
+	})
+
+	separator := 2 + 4*6 + 3
+	if got := img.RGBAAt(separator, 2+11+5); got != border {
+		t.Errorf("grid pixel: got %v, want %v", got, border)
+	}
+}
+
+func TestTableWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := imageTable().WritePNG(&buf, md.ImageStyle{}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() == 0 {
+		t.Error("empty image")
+	}
+}
@@ -0,0 +1,214 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+var contextEscapers = map[escapeContext]string{
+	contextText:    "_md_escape_text",
+	contextLinkEnd: "_md_escape_text",
+	contextLinkURL: "_md_escape_url",
+	contextCode:    "_md_escape_code",
+	contextPre:     "_md_escape_code",
+}
+
+type Template struct {
+	text      *template.Template
+	mu        sync.Mutex
+	escaped   bool
+	escapeErr error
+}
+
+func NewTemplate(name string) *Template {
+	t := template.New(name).Funcs(template.FuncMap{
+		"_md_escape_text": escaperFunc(contextText),
+		"_md_escape_url":  escaperFunc(contextLinkURL),
+		"_md_escape_code": escaperFunc(contextCode),
+	})
+	return &Template{text: t.Funcs(FuncMap())}
+}
+
+func MustTemplate(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func escaperFunc(c escapeContext) func(args ...interface{}) string {
+	return func(args ...interface{}) string {
+		if len(args) == 1 {
+			return c.escape(args[0])
+		}
+		return c.escape(fmt.Sprint(args...))
+	}
+}
+
+func (t *Template) Funcs(funcs template.FuncMap) *Template {
+	t.text.Funcs(funcs)
+	return t
+}
+
+func (t *Template) Parse(text string) (*Template, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.escaped {
+		return nil, fmt.Errorf("%s: cannot Parse after Execute", t.text.Name())
+	}
+	if _, err := t.text.Parse(text); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	t.mu.Lock()
+	if !t.escaped {
+		t.escaped = true
+		t.escapeErr = t.escape()
+	}
+	t.mu.Unlock()
+	if t.escapeErr != nil {
+		return t.escapeErr
+	}
+	return t.text.Execute(w, data)
+}
+
+func (t *Template) ExecuteStyled(data interface{}) (styledText, error) {
+	var out strings.Builder
+	if err := t.Execute(&out, data); err != nil {
+		return styledText{}, err
+	}
+	return styledText{text: out.String(), escaped: true}, nil
+}
+
+func (t *Template) escape() error {
+	for _, tmpl := range t.text.Templates() {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+		c, err := escapeList(tmpl.Tree.Root, contextText)
+		if err != nil {
+			return fmt.Errorf("%s: %v", tmpl.Name(), err)
+		}
+		if c != contextText && c != contextLinkEnd {
+			return fmt.Errorf("%s: template ends inside %s", tmpl.Name(), c)
+		}
+	}
+	return nil
+}
+
+func escapeList(list *parse.ListNode, c escapeContext) (escapeContext, error) {
+	if list == nil {
+		return c, nil
+	}
+	for _, n := range list.Nodes {
+		var err error
+		if c, err = escapeNode(n, c); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+func escapeNode(n parse.Node, c escapeContext) (escapeContext, error) {
+	switch n := n.(type) {
+	case *parse.TextNode:
+		return c.advance(string(n.Text)), nil
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 {
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Args:     []parse.Node{parse.NewIdentifier(contextEscapers[c]).SetPos(n.Pos)},
+			})
+		}
+		if c == contextLinkEnd {
+			return contextText, nil
+		}
+		return c, nil
+	case *parse.IfNode:
+		return escapeBranch(&n.BranchNode, c, false)
+	case *parse.WithNode:
+		return escapeBranch(&n.BranchNode, c, false)
+	case *parse.RangeNode:
+		return escapeBranch(&n.BranchNode, c, true)
+	case *parse.ListNode:
+		return escapeList(n, c)
+	case *parse.TemplateNode:
+		if c != contextText && c != contextLinkEnd {
+			return c, fmt.Errorf("template %q called inside %s", n.Name, c)
+		}
+		return contextText, nil
+	}
+	return c, nil
+}
+
+func escapeBranch(n *parse.BranchNode, c escapeContext, loop bool) (escapeContext, error) {
+	end, err := escapeList(n.List, c)
+	if err != nil {
+		return c, err
+	}
+	if loop && end != c {
+		return c, fmt.Errorf("loop body ends inside %s", end)
+	}
+	elseEnd, err := escapeList(n.ElseList, c)
+	if err != nil {
+		return c, err
+	}
+	if end != elseEnd {
+		return c, fmt.Errorf("branches end in different contexts: %s, %s", end, elseEnd)
+	}
+	return end, nil
+}
+
+func toStyled(value interface{}) styledText {
+	switch v := value.(type) {
+	case styledText:
+		v.escape()
+		return v
+	case string:
+		return Text(v)
+	case fmt.Stringer:
+		return Text(v.String())
+	}
+	return Text(fmt.Sprint(value))
+}
+
+func styledFunc(style func(input ...styledText) styledText) func(args ...interface{}) styledText {
+	return func(args ...interface{}) styledText {
+		input := make([]styledText, len(args))
+		for i, arg := range args {
+			input[i] = toStyled(arg)
+		}
+		return style(input...)
+	}
+}
+
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"text":           styledFunc(Combine),
+		"bold":           styledFunc(Bold),
+		"italic":         styledFunc(Italic),
+		"underline":      styledFunc(Underline),
+		"strikethrough":  styledFunc(Strikethrough),
+		"spoiler":        styledFunc(Spoiler),
+		"list":           styledFunc(BulletList),
+		"orderedList":    styledFunc(OrderedList),
+		"inlineURL":      InlineURL,
+		"inlineFixWidth": InlineFixWidth,
+		"codeBlock":      CodeBlock,
+		"mentionUser":    MentionUser,
+		"hashtag":        Hashtag,
+		"table": func(t *Table) styledText {
+			return t.Styled()
+		},
+		"keyValue": func(kv *KeyValue) styledText {
+			return kv.Styled()
+		},
+	}
+}
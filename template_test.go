@@ -0,0 +1,79 @@
+package telegrammarkdown_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+type deployment struct {
+	User    string
+	Version string
+	Env     string
+	URL     string
+	Notes   []string
+}
+
+func executeTemplate(t *testing.T, text string, data interface{}) string {
+	t.Helper()
+	tmpl, err := md.NewTemplate("test").Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestTemplateEscapesByContext(t *testing.T) {
+	data := deployment{User: "john.doe", Version: "v1.2-rc", Env: "prod`eu", URL: "https://x.io/a)b"}
+
+	got := executeTemplate(t, "*{{.User}}* deployed {{.Version}} to `{{.Env}}` [details]({{.URL}})", data)
+	want := "*john\\.doe* deployed v1\\.2\\-rc to `prod\\`eu` [details](https://x.io/a\\)b)"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTemplateStyledValuesVerbatim(t *testing.T) {
+	data := map[string]interface{}{"Name": md.BoldText("a.b")}
+
+	got := executeTemplate(t, "{{.Name}} `{{.Name}}`", data)
+	want := "*a\\.b* `a.b`"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTemplateFuncMap(t *testing.T) {
+	data := deployment{User: "bob", URL: "https://x.io", Notes: []string{"a.", "b!"}}
+
+	got := executeTemplate(t, `{{bold "by " .User}} {{inlineURL "link" .URL}}{{range .Notes}} {{italic .}}{{end}}`, data)
+	want := "*by bob* [link](https://x.io) _a\\._ _b\\!_"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTemplateCodeBlock(t *testing.T) {
+	got := executeTemplate(t, "```\n{{.}}\n```", "a\\b*c")
+	want := "```\na\\\\b*c\n```"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTemplateBranchContextsMustMatch(t *testing.T) {
+	tmpl := md.MustTemplate(md.NewTemplate("test").Parse("{{if .}}`{{end}}x"))
+
+	if err := tmpl.Execute(&strings.Builder{}, true); err == nil {
+		t.Error("expected error")
+	}
+}
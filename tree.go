@@ -0,0 +1,113 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	treeBranch   = "├── "
+	treeLast     = "└── "
+	treeVertical = "│   "
+	treeBlank    = "    "
+)
+
+type TreeNode struct {
+	Label    string
+	Style    func(input ...styledText) styledText
+	Children []TreeNode
+}
+
+func Node(label string, children ...TreeNode) TreeNode {
+	return TreeNode{Label: label, Children: children}
+}
+
+type Tree struct {
+	Root        TreeNode
+	CodeBlock   bool
+	MaxDepth    int
+	MaxChildren int
+	Indent      int
+}
+
+func (t Tree) String() string {
+	return t.Styled().String()
+}
+
+func (t Tree) Styled() styledText {
+	if t.CodeBlock {
+		lines := append([]string{t.Root.Label}, t.codeLines(t.Root, "", 1)...)
+		return CodeBlock("", strings.Join(lines, "\n")+"\n")
+	}
+	lines := append([]styledText{t.Root.styled()}, t.styledLines(t.Root, 1)...)
+	return CombineWithNewLine(lines...)
+}
+
+func (n TreeNode) styled() styledText {
+	if n.Style == nil {
+		return Text(n.Label)
+	}
+	return n.Style(Text(n.Label))
+}
+
+func (t Tree) visible(node TreeNode, depth int) (children []TreeNode, hidden int) {
+	if t.MaxDepth > 0 && depth > t.MaxDepth {
+		return nil, countNodes(node.Children)
+	}
+	children = node.Children
+	if t.MaxChildren > 0 && len(children) > t.MaxChildren {
+		for _, child := range children[t.MaxChildren:] {
+			hidden += 1 + countNodes(child.Children)
+		}
+		children = children[:t.MaxChildren]
+	}
+	return children, hidden
+}
+
+func (t Tree) codeLines(node TreeNode, prefix string, depth int) []string {
+	children, hidden := t.visible(node, depth)
+	var lines []string
+	for i, child := range children {
+		connector, next := treeBranch, treeVertical
+		if i == len(children)-1 && hidden == 0 {
+			connector, next = treeLast, treeBlank
+		}
+		lines = append(lines, prefix+connector+child.Label)
+		lines = append(lines, t.codeLines(child, prefix+next, depth+1)...)
+	}
+	if hidden > 0 {
+		lines = append(lines, prefix+treeLast+moreLabel(hidden))
+	}
+	return lines
+}
+
+func (t Tree) styledLines(node TreeNode, depth int) []styledText {
+	indent := t.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	prefix := styledText{text: strings.Repeat(" ", indent*depth), escaped: true}
+
+	children, hidden := t.visible(node, depth)
+	var lines []styledText
+	for _, child := range children {
+		lines = append(lines, Combine(prefix, child.styled()))
+		lines = append(lines, t.styledLines(child, depth+1)...)
+	}
+	if hidden > 0 {
+		lines = append(lines, Combine(prefix, ItalicText(moreLabel(hidden))))
+	}
+	return lines
+}
+
+func countNodes(nodes []TreeNode) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		count += countNodes(node.Children)
+	}
+	return count
+}
+
+func moreLabel(hidden int) string {
+	return fmt.Sprintf("… %d more", hidden)
+}
@@ -0,0 +1,76 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func sampleTree() md.TreeNode {
+	return md.Node("src",
+		md.Node("cmd",
+			md.Node("main.go"),
+		),
+		md.Node("pkg",
+			md.Node("a.go"),
+			md.Node("b.go"),
+			md.Node("c.go"),
+		),
+		md.Node("go.mod"),
+	)
+}
+
+func TestTreeCodeBlock(t *testing.T) {
+	got := md.Tree{Root: sampleTree(), CodeBlock: true}.String()
+	want := "```\n"
+	want += "src\n"
+	want += "├── cmd\n"
+	want += "│   └── main.go\n"
+	want += "├── pkg\n"
+	want += "│   ├── a.go\n"
+	want += "│   ├── b.go\n"
+	want += "│   └── c.go\n"
+	want += "└── go.mod\n"
+	want += "```\n"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTreeCollapsing(t *testing.T) {
+	got := md.Tree{Root: sampleTree(), CodeBlock: true, MaxChildren: 2, MaxDepth: 1}.String()
+	want := "```\n"
+	want += "src\n"
+	want += "├── cmd\n"
+	want += "│   └── … 1 more\n"
+	want += "├── pkg\n"
+	want += "│   └── … 3 more\n"
+	want += "└── … 1 more\n"
+	want += "```\n"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTreeStyled(t *testing.T) {
+	root := md.Node("deps", md.Node("a.b", md.Node("c")))
+	root.Style = md.Bold
+
+	got := md.Tree{Root: root}.String()
+	want := "*deps*\n  a\\.b\n    c"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestTreeCodeBlockEscapes(t *testing.T) {
+	got := md.Tree{Root: md.Node("`root`"), CodeBlock: true}.String()
+	want := "```\n\\`root\\`\n```\n"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
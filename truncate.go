@@ -0,0 +1,66 @@
+package telegrammarkdown
+
+func Truncate(input styledText, n int, ellipsis string) styledText {
+	input.escape()
+	nodes := parseNodes(input.text)
+	if visibleLength(nodes) <= n {
+		return input
+	}
+
+	budget := n - graphemeCount(ellipsis)
+	if budget < 0 {
+		budget = 0
+		ellipsis, _ = takeGraphemes(ellipsis, n)
+	}
+	truncated, _ := truncateNodes(nodes, budget, ellipsis)
+	return styledText{text: render(truncated), escaped: true}
+}
+
+func visibleLength(nodes []*node) int {
+	length := 0
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode, codeNode, preNode:
+			length += graphemeCount(n.text)
+		default:
+			length += visibleLength(n.children)
+		}
+	}
+	return length
+}
+
+// truncateNodes keeps budget visible characters of nodes and reports whether
+// it had to cut, in which case ellipsis has been placed at the cut.
+func truncateNodes(nodes []*node, budget int, ellipsis string) ([]*node, bool) {
+	var kept []*node
+	for _, n := range nodes {
+		switch n.kind {
+		case quoteNode:
+			kept = append(kept, n)
+		case textNode, codeNode, preNode:
+			text, cut := takeGraphemes(n.text, budget)
+			budget -= graphemeCount(text)
+			if !cut {
+				kept = append(kept, n)
+				continue
+			}
+			truncated := *n
+			truncated.text = text + ellipsis
+			return append(kept, &truncated), true
+		default:
+			children, cut := truncateNodes(n.children, budget, ellipsis)
+			budget -= visibleLength(children)
+			truncated := *n
+			truncated.children = children
+			if !cut {
+				kept = append(kept, &truncated)
+				continue
+			}
+			if len(children) > 0 {
+				kept = append(kept, &truncated)
+			}
+			return kept, true
+		}
+	}
+	return kept, false
+}
@@ -0,0 +1,92 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestTruncateShortText(t *testing.T) {
+	input := md.BoldText("short")
+
+	got := md.Truncate(input, 10, "…")
+
+	if got != input {
+		t.Error(errorMessage(got.String(), input.String()))
+	}
+}
+
+func TestTruncateCountsVisibleCharacters(t *testing.T) {
+	input := md.Combine(md.Text("a.b.c"), md.BoldText("d.e.f"))
+
+	got := md.Truncate(input, 7, "…")
+	want := `a\.b\.c*d…*`
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestTruncateClosesNestedEntities(t *testing.T) {
+	input := md.Bold(md.Text("bold "), md.Italic(md.Text("italic text")), md.Text(" tail"))
+
+	got := md.Truncate(input, 9, "...")
+	want := `*bold _i\.\.\._*`
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestTruncateKeepsUnderlineInItalic(t *testing.T) {
+	input := md.Combine(md.Italic(md.Text("a "), md.UnderlineText("b"), md.Text(" c")), md.Text("zzzzzzzzzzzzzz"))
+
+	got := md.Truncate(input, 8, "…")
+	want := "_a __b__ c_zz…"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+
+	input = md.FromHTML("<i><u>under</u> line</i>")
+
+	got = md.Truncate(input, 8, "…")
+	want = "_\r__under__ l…_"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestTruncateKeepsLinkURL(t *testing.T) {
+	input := md.CombineWithSpace(md.InlineURL("a long link text", "https://example.com/(x)"), md.Text("after"))
+
+	got := md.Truncate(input, 7, "…")
+	want := `[a long…](https://example.com/(x\))`
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestTruncateInsideCode(t *testing.T) {
+	input := md.InlineFixWidth("a`b`c")
+
+	got := md.Truncate(input, 3, "…")
+	want := "`a\\`…`"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestTruncateGraphemeClusters(t *testing.T) {
+	input := md.Text("👍🏽🇹🇷éxyz")
+
+	got := md.Truncate(input, 4, "…")
+	want := "👍🏽🇹🇷é…"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
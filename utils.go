@@ -1,10 +1,12 @@
 package telegrammarkdown
 
 import (
-	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
+const newLine = "\n"
+
 func Combine(input ...styledText) styledText {
 	return styledText{
 		text:    getCombinedText(input...),
@@ -21,21 +23,13 @@ func CombineWithSpace(input ...styledText) styledText {
 
 func CombineWithNewLine(input ...styledText) styledText {
 	return styledText{
-		text:    getCombinedTextWithSeparator(`\\n`, input...),
+		text:    getCombinedTextWithSeparator(newLine, input...),
 		escaped: true,
 	}
 }
 
 func escape(input, charset string) string {
-	replacementPairs := make([]string, 0, 2*len(charset))
-
-	for _, ch := range charset {
-		replacementPairs = append(replacementPairs, string(ch))
-		replacementPairs = append(replacementPairs, `\`+string(ch))
-	}
-	replacer := strings.NewReplacer(replacementPairs...)
-
-	return replacer.Replace(input)
+	return escaperFor(charset).escape(input)
 }
 
 func replaceSpaces(input string) string {
@@ -47,7 +41,9 @@ func replaceSpaces(input string) string {
 		" ", `\_`,
 		"\\-", `\_`,
 		"-", `\_`,
-		`\n`, `\_`,
+		"\r\n", `\_`,
+		"\n", `\_`,
+		"\r", `\_`,
 	}
 	replacer := strings.NewReplacer(replacements...)
 	return replacer.Replace(input)
@@ -58,20 +54,47 @@ func getCombinedText(input ...styledText) string {
 }
 
 func getCombinedTextWithSeparator(separator string, input ...styledText) string {
-	var combined string
-	for _, s := range input {
-		s.escape()
-		combined += s.text + separator
+	if len(input) == 0 {
+		return ""
+	}
+	size := len(separator) * (len(input) - 1)
+	for i := range input {
+		input[i].escape()
+		size += len(input[i].text)
 	}
 
-	return strings.TrimSuffix(combined, separator)
+	var combined strings.Builder
+	combined.Grow(size)
+	for i, s := range input {
+		if i > 0 {
+			combined.WriteString(separator)
+		}
+		combined.WriteString(s.text)
+	}
+	return combined.String()
 }
 
 func encloseText(input, prefix, suffix string) string {
-	return fmt.Sprintf("%s%s%s", prefix, input, suffix)
+	return prefix + input + suffix
 }
 
 func combineAndEnclose(closure string, input ...styledText) styledText {
 	combined := getCombinedText(input...)
 	return styledText{text: encloseText(combined, closure, closure), escaped: true}
 }
+
+func alignText(input string, width int, align Alignment) string {
+	padding := width - utf8.RuneCountInString(input)
+	if padding <= 0 {
+		return input
+	}
+	switch align {
+	case Left:
+		return input + strings.Repeat(" ", padding)
+	case Center:
+		left := padding / 2
+		return strings.Repeat(" ", left) + input + strings.Repeat(" ", padding-left)
+	default:
+		return strings.Repeat(" ", padding) + input
+	}
+}
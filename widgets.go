@@ -0,0 +1,112 @@
+package telegrammarkdown
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	partialBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	sparkBlocks   = []rune("▁▂▃▄▅▆▇█")
+)
+
+const (
+	gaugeOK       = "🟢"
+	gaugeWarning  = "🟡"
+	gaugeCritical = "🔴"
+)
+
+type Thresholds struct {
+	Warning  float64
+	Critical float64
+}
+
+func (t Thresholds) emoji(value float64) string {
+	if t.Critical < t.Warning {
+		switch {
+		case value <= t.Critical:
+			return gaugeCritical
+		case value <= t.Warning:
+			return gaugeWarning
+		}
+		return gaugeOK
+	}
+	switch {
+	case value >= t.Critical:
+		return gaugeCritical
+	case value >= t.Warning:
+		return gaugeWarning
+	}
+	return gaugeOK
+}
+
+func ratio(value, max float64) float64 {
+	r := value / max
+	if max <= 0 || math.IsNaN(r) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, r))
+}
+
+// position returns where value lies between min and max, from 0 to 1. The
+// operands are halved so that the differences cannot overflow.
+func position(value, min, max float64) float64 {
+	return ratio(value/2-min/2, max/2-min/2)
+}
+
+func ProgressBar(value, max float64, width int) styledText {
+	if width <= 0 {
+		return styledText{escaped: true}
+	}
+	eighths := int(math.Round(ratio(value, max) * float64(width*8)))
+	full, partial := eighths/8, eighths%8
+
+	bar := strings.Repeat("█", full) + partialBlocks[partial]
+	if partial > 0 {
+		full++
+	}
+	bar += strings.Repeat(" ", width-full)
+	return styledText{text: bar, escaped: true}
+}
+
+func ProgressBarWithPercent(value, max float64, width int) styledText {
+	percent := fmt.Sprintf("%3d%%", int(math.Round(ratio(value, max)*100)))
+	return CombineWithSpace(ProgressBar(value, max, width), Text(percent))
+}
+
+func Gauge(value, max float64, width int, thresholds Thresholds) styledText {
+	return CombineWithSpace(
+		styledText{text: thresholds.emoji(value), escaped: true},
+		ProgressBarWithPercent(value, max, width),
+	)
+}
+
+func Sparkline(values []float64) styledText {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	spark := make([]rune, 0, len(values))
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			spark = append(spark, ' ')
+		case math.IsInf(v, 1):
+			spark = append(spark, sparkBlocks[len(sparkBlocks)-1])
+		case math.IsInf(v, -1):
+			spark = append(spark, sparkBlocks[0])
+		case max == min:
+			spark = append(spark, sparkBlocks[len(sparkBlocks)/2-1])
+		default:
+			level := int(math.Round(position(v, min, max) * float64(len(sparkBlocks)-1)))
+			spark = append(spark, sparkBlocks[level])
+		}
+	}
+	return styledText{text: string(spark), escaped: true}
+}
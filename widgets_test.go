@@ -0,0 +1,118 @@
+package telegrammarkdown_test
+
+import (
+	"math"
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestProgressBar(t *testing.T) {
+	got := md.ProgressBar(45, 100, 4)
+	want := "█▊  "
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestProgressBarClamps(t *testing.T) {
+	got := md.ProgressBar(150, 100, 3)
+	want := "███"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestProgressBarWithPercent(t *testing.T) {
+	got := md.ProgressBarWithPercent(1, 4, 4)
+	want := "█     25%"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestGauge(t *testing.T) {
+	thresholds := md.Thresholds{Warning: 70, Critical: 90}
+
+	got := md.Gauge(75, 100, 2, thresholds)
+	want := "🟡 █▌  75%"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestGaugeInvertedThresholds(t *testing.T) {
+	thresholds := md.Thresholds{Warning: 20, Critical: 10}
+
+	got := md.Gauge(10, 100, 1, thresholds)
+	want := "🔴 ▏  10%"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	got := md.Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7, math.NaN()})
+	want := "▁▂▃▄▅▆▇█ "
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestSparklineInTable(t *testing.T) {
+	table := md.Table{}
+	table.AddColumns(md.Column{Width: 5})
+	table.AddRow(md.Sparkline([]float64{1, 1, 1}).String())
+
+	got := table.String()
+	want := "`  ▄▄▄`"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestProgressBarNonFinite(t *testing.T) {
+	tests := []struct {
+		value, max float64
+		want       string
+	}{
+		{1, math.NaN(), "    "},
+		{math.Inf(1), math.Inf(1), "    "},
+		{math.Inf(1), 10, "████"},
+		{5, math.Inf(1), "    "},
+	}
+	for _, tt := range tests {
+		if got := md.ProgressBar(tt.value, tt.max, 4).String(); got != tt.want {
+			t.Errorf("ProgressBar(%v, %v): %s", tt.value, tt.max, errorMessage(got, tt.want))
+		}
+	}
+}
+
+func TestSparklineNonFinite(t *testing.T) {
+	got := md.Sparkline([]float64{0, math.Inf(1), 7, math.Inf(-1), math.NaN()})
+	want := "▁██▁ "
+
+	if got.String() != want {
+		t.Error(errorMessage(got.String(), want))
+	}
+
+	got = md.Sparkline([]float64{math.Inf(1), math.Inf(-1)})
+	want = "█▁"
+
+	if got.String() != want {
+		t.Error(errorMessage(got.String(), want))
+	}
+	got = md.Sparkline([]float64{-math.MaxFloat64, math.MaxFloat64, 0})
+	want = "▁█▅"
+
+	if got.String() != want {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
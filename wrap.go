@@ -0,0 +1,254 @@
+package telegrammarkdown
+
+import "strings"
+
+const (
+	plainRun = -1
+	quoteRun = -2
+)
+
+type cell struct {
+	text  string
+	width int
+	run   int
+}
+
+type run struct {
+	path []*node
+	leaf *node
+}
+
+type WrapOptions struct {
+	Indent     string
+	BreakWords bool
+}
+
+func Wrap(input styledText, width int) styledText {
+	return WrapOptions{BreakWords: true}.Wrap(input, width)
+}
+
+func WrapText(input string, width int) string {
+	return WrapOptions{BreakWords: true}.WrapText(input, width)
+}
+
+func (o WrapOptions) WrapText(input string, width int) string {
+	lines := o.wrapCells(textCells(input, plainRun), width)
+	wrapped := make([]string, len(lines))
+	for i, line := range lines {
+		for _, c := range line {
+			wrapped[i] += c.text
+		}
+	}
+	return strings.Join(wrapped, newLine)
+}
+
+func (o WrapOptions) Wrap(input styledText, width int) styledText {
+	input.escape()
+	var out strings.Builder
+	var segment []*node
+	flush := func() {
+		if len(segment) == 0 {
+			return
+		}
+		var runs []run
+		cells := flattenCells(segment, nil, &runs)
+		for i, line := range o.wrapCells(cells, width) {
+			if i > 0 {
+				out.WriteString(newLine)
+			}
+			out.WriteString(render(buildLine(line, runs)))
+		}
+		segment = nil
+	}
+	for _, n := range parseNodes(input.text) {
+		if n.kind == preNode {
+			flush()
+			out.WriteString(render([]*node{n}))
+			continue
+		}
+		segment = append(segment, n)
+	}
+	flush()
+	return styledText{text: out.String(), escaped: true}
+}
+
+func textCells(input string, run int) []cell {
+	var cells []cell
+	for len(input) > 0 {
+		size := nextGrapheme(input)
+		cells = append(cells, cell{text: input[:size], width: graphemeWidth(input[:size]), run: run})
+		input = input[size:]
+	}
+	return cells
+}
+
+func flattenCells(nodes []*node, path []*node, runs *[]run) []cell {
+	var cells []cell
+	for _, n := range nodes {
+		switch n.kind {
+		case quoteNode:
+			cells = append(cells, cell{text: ">", run: quoteRun})
+		case textNode, codeNode, preNode:
+			*runs = append(*runs, run{path: path, leaf: n})
+			cells = append(cells, textCells(n.text, len(*runs)-1)...)
+		default:
+			nested := append(append([]*node{}, path...), n)
+			cells = append(cells, flattenCells(n.children, nested, runs)...)
+		}
+	}
+	return cells
+}
+
+func cellsWidth(cells []cell) int {
+	width := 0
+	for _, c := range cells {
+		width += c.width
+	}
+	return width
+}
+
+func (o WrapOptions) wrapCells(cells []cell, width int) [][]cell {
+	var lines [][]cell
+	start := 0
+	for i, c := range cells {
+		if c.text == newLine || c.text == "\r\n" || c.text == "\r" {
+			lines = append(lines, o.wrapLine(cells[start:i], width)...)
+			start = i + 1
+		}
+	}
+	return append(lines, o.wrapLine(cells[start:], width)...)
+}
+
+func (o WrapOptions) wrapLine(source []cell, width int) [][]cell {
+	var prefix []cell
+	for len(source) > 0 && source[0].run == quoteRun {
+		prefix = append(prefix, source[0])
+		source = source[1:]
+	}
+	indent := append(append([]cell{}, prefix...), textCells(o.Indent, plainRun)...)
+
+	var lines [][]cell
+	line := append([]cell{}, prefix...)
+	lineWidth, hasContent := 0, false
+	var pendingSpace []cell
+	breakLine := func() {
+		lines = append(lines, line)
+		line = append([]cell{}, indent...)
+		lineWidth, hasContent = cellsWidth(indent), false
+		pendingSpace = nil
+	}
+	place := func(word []cell) {
+		for len(word) > 0 {
+			available := width - lineWidth
+			if cellsWidth(word) <= available || !o.BreakWords {
+				line = append(line, word...)
+				lineWidth += cellsWidth(word)
+				hasContent = true
+				return
+			}
+			if hasContent {
+				breakLine()
+				continue
+			}
+			n, taken := 0, 0
+			for n < len(word) && taken+word[n].width <= available {
+				taken += word[n].width
+				n++
+			}
+			if n == 0 {
+				n, taken = 1, word[0].width
+			}
+			line = append(line, word[:n]...)
+			lineWidth += taken
+			word = word[n:]
+			if len(word) > 0 {
+				breakLine()
+			}
+		}
+	}
+
+	for _, token := range splitCellWords(source) {
+		if token[0].text == " " || token[0].text == "\t" {
+			if !hasContent && len(lines) == 0 {
+				line = append(line, token...)
+				lineWidth += cellsWidth(token)
+			} else {
+				pendingSpace = token
+			}
+			continue
+		}
+		if hasContent && lineWidth+cellsWidth(pendingSpace)+cellsWidth(token) > width {
+			breakLine()
+		} else {
+			line = append(line, pendingSpace...)
+			lineWidth += cellsWidth(pendingSpace)
+		}
+		pendingSpace = nil
+		place(token)
+	}
+	return append(lines, line)
+}
+
+func splitCellWords(cells []cell) [][]cell {
+	var words [][]cell
+	start := 0
+	isSpace := func(c cell) bool { return c.text == " " || c.text == "\t" }
+	for i := 1; i <= len(cells); i++ {
+		if i == len(cells) || isSpace(cells[i]) != isSpace(cells[start]) {
+			words = append(words, cells[start:i])
+			start = i
+		}
+	}
+	return words
+}
+
+func buildLine(cells []cell, runs []run) []*node {
+	root := &node{}
+	var openPath, openCopies []*node
+	container := func() *node {
+		if len(openCopies) == 0 {
+			return root
+		}
+		return openCopies[len(openCopies)-1]
+	}
+
+	for i := 0; i < len(cells); {
+		j := i
+		var text strings.Builder
+		for j < len(cells) && cells[j].run == cells[i].run {
+			text.WriteString(cells[j].text)
+			j++
+		}
+
+		switch r := cells[i].run; r {
+		case plainRun:
+			openPath, openCopies = nil, nil
+			root.appendText(text.String())
+		case quoteRun:
+			openPath, openCopies = nil, nil
+			root.children = append(root.children, &node{kind: quoteNode})
+		default:
+			path := runs[r].path
+			common := 0
+			for common < len(openPath) && common < len(path) && openPath[common] == path[common] {
+				common++
+			}
+			openPath, openCopies = openPath[:common], openCopies[:common]
+			for _, n := range path[common:] {
+				copied := &node{kind: n.kind, url: n.url, language: n.language}
+				container().children = append(container().children, copied)
+				openPath = append(openPath, n)
+				openCopies = append(openCopies, copied)
+			}
+			leaf := runs[r].leaf
+			if leaf.kind == textNode {
+				container().appendText(text.String())
+			} else {
+				container().children = append(container().children,
+					&node{kind: leaf.kind, text: text.String(), language: leaf.language})
+			}
+		}
+		i = j
+	}
+	return root.children
+}
@@ -0,0 +1,83 @@
+package telegrammarkdown_test
+
+import (
+	"testing"
+
+	md "github.com/onuruluag/telegram-markdown-go"
+)
+
+func TestWrapText(t *testing.T) {
+	got := md.WrapText("the quick brown fox jumps over", 10)
+	want := "the quick\nbrown fox\njumps over"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapTextLineEndings(t *testing.T) {
+	got := md.WrapText("a\r\nb c d\re", 2)
+	want := "a\nb\nc\nd\ne"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapTextHangingIndent(t *testing.T) {
+	got := md.WrapOptions{Indent: "  "}.WrapText("- one two three four", 9)
+	want := "- one two\n  three\n  four"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapTextBreaksLongWords(t *testing.T) {
+	got := md.WrapText("hash 0123456789abcdef", 8)
+	want := "hash\n01234567\n89abcdef"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapTextKeepsLongWords(t *testing.T) {
+	got := md.WrapOptions{}.WrapText("see https://example.com/path", 8)
+	want := "see\nhttps://example.com/path"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapTextDisplayWidth(t *testing.T) {
+	got := md.WrapText("日本語 テキスト", 8)
+	want := "日本語\nテキスト"
+
+	if got != want {
+		t.Error(errorMessage(got, want))
+	}
+}
+
+func TestWrapReappliesFormatting(t *testing.T) {
+	input := md.Combine(md.Text("plain "), md.Bold(md.Text("bold "), md.ItalicText("and italic words")))
+
+	got := md.Wrap(input, 12)
+	want := "plain *bold*\n*_and italic_*\n*_words_*"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}
+
+func TestWrapLinksAndCode(t *testing.T) {
+	input := md.CombineWithSpace(md.InlineURL("docs page", "https://go.dev"), md.InlineFixWidth("go vet"))
+
+	got := md.Wrap(input, 5)
+	want := "[docs](https://go.dev)\n[page](https://go.dev)\n`go`\n`vet`"
+
+	if !got.Equals(want) {
+		t.Error(errorMessage(got.String(), want))
+	}
+}